@@ -0,0 +1,357 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+const apiPath = "/rest/api/1.0"
+
+type BitbucketServiceImpl struct {
+	Client  rest.RestClient
+	BaseURL string
+	Token   string
+	Project string
+}
+
+type repository struct {
+	ID          int    `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Project     struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+type repositoryBase struct {
+	IsLastPage    bool         `json:"isLastPage"`
+	NextPageStart int          `json:"nextPageStart"`
+	Values        []repository `json:"values"`
+}
+
+type ref struct {
+	ID         string         `json:"id"`
+	DisplayID  string         `json:"displayId,omitempty"`
+	Repository *refRepository `json:"repository,omitempty"`
+}
+
+type refRepository struct {
+	Slug    string `json:"slug"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+type prRequest struct {
+	Version     *int   `json:"version,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	FromRef     *ref   `json:"fromRef,omitempty"`
+	ToRef       ref    `json:"toRef"`
+	Draft       bool   `json:"draft,omitempty"`
+}
+
+type prResponse struct {
+	ID      int    `json:"id"`
+	Version int    `json:"version"`
+	Title   string `json:"title"`
+	ToRef   ref    `json:"toRef"`
+	Links   struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+type prBase struct {
+	Values []prResponse `json:"values"`
+}
+
+// Bitbucket doesn't tell who the user is, so this only checks the token.
+func (b BitbucketServiceImpl) CheckAuth(ctx context.Context) (string, error) {
+	url := b.BaseURL + apiPath + "/profile/recent/repos?limit=1"
+
+	req, err := b.Client.CreateRequest(ctx, http.MethodGet, url, b.headers(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := b.Client.DoRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	err = b.Client.ProcessResponse(response, new(repositoryBase))
+	if err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+func (b BitbucketServiceImpl) SearchProject(ctx context.Context, search string) ([]host.Repository, error) {
+	var repositories []host.Repository
+
+	for start := 0; ; {
+		params := url.Values{}
+		params.Set("name", search)
+		params.Set("start", fmt.Sprintf("%d", start))
+		if b.Project != "" {
+			params.Set("projectkey", b.Project)
+		}
+		url := b.BaseURL + apiPath + "/repos?" + params.Encode()
+
+		req, err := b.Client.CreateRequest(ctx, http.MethodGet, url, b.headers(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := b.Client.DoRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		page := new(repositoryBase)
+		err = b.Client.ProcessResponse(response, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page.Values {
+			repositories = append(repositories, r.toRepository())
+		}
+
+		if page.IsLastPage || len(page.Values) == 0 {
+			return repositories, nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// Only the project key and slug are used, so "scm/KEY/slug" works as well.
+func (b BitbucketServiceImpl) GetProject(ctx context.Context, fullPath string) (host.Repository, error) {
+	segments := strings.Split(strings.Trim(fullPath, "/"), "/")
+	if len(segments) < 2 {
+		return host.Repository{}, fmt.Errorf("\"%s\" is not a project key and repository slug", fullPath)
+	}
+	projectKey, slug := segments[len(segments)-2], segments[len(segments)-1]
+	uri := fmt.Sprintf("%s/projects/%s/repos/%s", apiPath, url.PathEscape(projectKey), url.PathEscape(slug))
+	url := b.BaseURL + uri
+
+	req, err := b.Client.CreateRequest(ctx, http.MethodGet, url, b.headers(), nil)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	response, err := b.Client.DoRequest(req)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	foundRepository := new(repository)
+	err = b.Client.ProcessResponse(response, foundRepository)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	return foundRepository.toRepository(), nil
+}
+
+func (b BitbucketServiceImpl) CreateMergeRequest(ctx context.Context, repository host.Repository, options host.MergeRequestOptions) (host.MergeRequestResponse, error) {
+	if err := checkOptions(options); err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	from := branchRef(repository, options.SourceBranch)
+	request := prRequest{
+		Title:       options.Title,
+		Description: options.Description,
+		FromRef:     &from,
+		ToRef:       branchRef(repository, options.TargetBranch),
+		Draft:       options.Draft}
+
+	pullRequest, err := b.sendPullRequest(ctx, http.MethodPost, pullRequestsUri(repository), request)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	return pullRequest.toMergeRequestResponse(), nil
+}
+
+func (b BitbucketServiceImpl) FindMergeRequest(ctx context.Context, repository host.Repository, sourceBranch string) (host.MergeRequestResponse, bool, error) {
+	params := url.Values{}
+	params.Set("state", "OPEN")
+	params.Set("direction", "OUTGOING")
+	params.Set("at", "refs/heads/"+sourceBranch)
+	url := b.BaseURL + pullRequestsUri(repository) + "?" + params.Encode()
+
+	req, err := b.Client.CreateRequest(ctx, http.MethodGet, url, b.headers(), nil)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	response, err := b.Client.DoRequest(req)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	pullRequests := new(prBase)
+	err = b.Client.ProcessResponse(response, pullRequests)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	if len(pullRequests.Values) == 0 {
+		return host.MergeRequestResponse{}, false, nil
+	}
+
+	return pullRequests.Values[0].toMergeRequestResponse(), true, nil
+}
+
+func (b BitbucketServiceImpl) UpdateMergeRequest(ctx context.Context, repository host.Repository, iid int, options host.MergeRequestOptions) (host.MergeRequestResponse, error) {
+	if err := checkOptions(options); err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	uri := fmt.Sprintf("%s/%d", pullRequestsUri(repository), iid)
+	url := b.BaseURL + uri
+
+	req, err := b.Client.CreateRequest(ctx, http.MethodGet, url, b.headers(), nil)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	response, err := b.Client.DoRequest(req)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	current := new(prResponse)
+	err = b.Client.ProcessResponse(response, current)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	request := prRequest{
+		Version:     &current.Version,
+		Title:       options.Title,
+		Description: options.Description,
+		ToRef:       branchRef(repository, options.TargetBranch),
+		Draft:       options.Draft}
+
+	pullRequest, err := b.sendPullRequest(ctx, http.MethodPut, uri, request)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	return pullRequest.toMergeRequestResponse(), nil
+}
+
+func (b BitbucketServiceImpl) sendPullRequest(ctx context.Context, method string, uri string, request prRequest) (prResponse, error) {
+	pullRequest := new(prResponse)
+	url := b.BaseURL + uri
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	req, err := b.Client.CreateRequest(ctx, method, url, b.headers(), bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	response, err := b.Client.DoRequest(req)
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	err = b.Client.ProcessResponse(response, pullRequest)
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	return *pullRequest, nil
+}
+
+func (b BitbucketServiceImpl) headers() map[string]string {
+	return map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", b.Token),
+		"Accept":        "application/json",
+		"Content-Type":  "application/json"}
+}
+
+func checkOptions(options host.MergeRequestOptions) error {
+	var unsupported []string
+
+	if len(options.Labels) > 0 {
+		unsupported = append(unsupported, "labels")
+	}
+	if len(options.AssigneeIDs) > 0 {
+		unsupported = append(unsupported, "assignee IDs")
+	}
+	if len(options.ReviewerIDs) > 0 {
+		unsupported = append(unsupported, "reviewer IDs")
+	}
+	if options.MilestoneID != 0 {
+		unsupported = append(unsupported, "milestone")
+	}
+
+	if len(unsupported) > 0 {
+		return errors.New(strings.Join(unsupported, ", ") + " not supported on Bitbucket")
+	}
+
+	return nil
+}
+
+func pullRequestsUri(repository host.Repository) string {
+	projectKey, slug := splitFullPath(repository)
+
+	return fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests", apiPath, url.PathEscape(projectKey), url.PathEscape(slug))
+}
+
+func branchRef(repository host.Repository, branch string) ref {
+	projectKey, slug := splitFullPath(repository)
+	refRepo := &refRepository{Slug: slug}
+	refRepo.Project.Key = projectKey
+
+	return ref{ID: "refs/heads/" + branch, Repository: refRepo}
+}
+
+func splitFullPath(repository host.Repository) (string, string) {
+	parts := strings.SplitN(repository.FullPath, "/", 2)
+	if len(parts) < 2 {
+		return "", repository.Path
+	}
+
+	return parts[0], parts[1]
+}
+
+func (r repository) toRepository() host.Repository {
+	return host.Repository{
+		ID:          r.ID,
+		Description: r.Description,
+		Name:        r.Name,
+		Path:        r.Slug,
+		FullPath:    fmt.Sprintf("%s/%s", r.Project.Key, r.Slug),
+		Host:        host.Bitbucket}
+}
+
+func (p prResponse) toMergeRequestResponse() host.MergeRequestResponse {
+	response := host.MergeRequestResponse{IID: p.ID, Title: p.Title, TargetBranch: p.ToRef.DisplayID}
+	if len(p.Links.Self) > 0 {
+		response.Url = p.Links.Self[0].Href
+	}
+
+	return response
+}
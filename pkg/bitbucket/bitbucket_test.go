@@ -0,0 +1,144 @@
+package bitbucket_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/bitbucket"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/mocks"
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+var repository = host.Repository{ID: 1, Name: "Jitlab", Path: "jitlab", FullPath: "TOOLS/jitlab", Host: host.Bitbucket}
+
+func TestSearchProject(t *testing.T) {
+	pages := map[string]string{
+		"0": `{"isLastPage":false,"nextPageStart":1,"values":[{"id":1,"slug":"jitlab","name":"Jitlab","project":{"key":"TOOLS"}}]}`,
+		"1": `{"isLastPage":true,"values":[{"id":2,"slug":"jitlab-docs","name":"Jitlab docs","project":{"key":"DOCS"}}]}`,
+	}
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		return mocks.JsonResponse(200, pages[req.URL.Query().Get("start")]), nil
+	}
+	bitbucketService := bitbucket.BitbucketServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	repositories, err := bitbucketService.SearchProject(context.Background(), "jitlab")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if len(repositories) != 2 || repositories[1].FullPath != "DOCS/jitlab-docs" || repositories[1].Host != host.Bitbucket {
+		t.Errorf("Got unexpected repositories '%+v'", repositories)
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	tests := map[string]struct {
+		fullPath     string
+		expectedPath string
+	}{
+		"SSH clone path":   {"tools/jitlab", "/rest/api/1.0/projects/tools/repos/jitlab"},
+		"HTTPS clone path": {"scm/tools/jitlab", "/rest/api/1.0/projects/tools/repos/jitlab"},
+		"Invalid path":     {"jitlab", ""},
+	}
+	bitbucketService := bitbucket.BitbucketServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPath string
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				gotPath = req.URL.Path
+				return mocks.JsonResponse(200, `{"id":1,"slug":"jitlab","name":"Jitlab","project":{"key":"TOOLS"}}`), nil
+			}
+
+			result, err := bitbucketService.GetProject(context.Background(), tc.fullPath)
+
+			if tc.expectedPath == "" {
+				if err == nil {
+					t.Errorf("Got repository '%+v', but wanted an error", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if gotPath != tc.expectedPath || result.FullPath != "TOOLS/jitlab" {
+				t.Errorf("Got repository '%+v' from '%s', wanted path '%s'", result, gotPath, tc.expectedPath)
+			}
+		})
+	}
+}
+
+func TestCreateMergeRequest(t *testing.T) {
+	tests := map[string]struct {
+		options       host.MergeRequestOptions
+		expectedError bool
+	}{
+		"Pull request":             {host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "Title"}, false},
+		"Pull request with labels": {host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "Title", Labels: []string{"bug"}}, true},
+	}
+	bitbucketService := bitbucket.BitbucketServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPath string
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				gotPath = req.URL.Path
+				return mocks.JsonResponse(201, `{"id":5,"version":0,"title":"Title","toRef":{"id":"refs/heads/master","displayId":"master"},"links":{"self":[{"href":"http://www.example.com/projects/TOOLS/repos/jitlab/pull-requests/5"}]}}`), nil
+			}
+
+			result, err := bitbucketService.CreateMergeRequest(context.Background(), repository, tc.options)
+
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Got pull request '%+v', but wanted an error", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if gotPath != "/rest/api/1.0/projects/TOOLS/repos/jitlab/pull-requests" {
+				t.Errorf("Got unexpected path '%s'", gotPath)
+			}
+
+			if result.IID != 5 || result.TargetBranch != "master" || result.Url != "http://www.example.com/projects/TOOLS/repos/jitlab/pull-requests/5" {
+				t.Errorf("Got unexpected pull request '%+v'", result)
+			}
+		})
+	}
+}
+
+func TestUpdateMergeRequest(t *testing.T) {
+	var requests []string
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method)
+		if req.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(req.Body)
+			if !bytes.Contains(body, []byte(`"version":3`)) {
+				t.Errorf("Update doesn't carry the current version: %s", body)
+			}
+			if !bytes.Contains(body, []byte(`"draft":true`)) {
+				t.Errorf("Update doesn't mark the pull request as draft: %s", body)
+			}
+		}
+		return mocks.JsonResponse(200, `{"id":5,"version":3,"title":"Title","toRef":{"displayId":"master"},"links":{"self":[{"href":"http://www.example.com/pr/5"}]}}`), nil
+	}
+	bitbucketService := bitbucket.BitbucketServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	_, err := bitbucketService.UpdateMergeRequest(context.Background(), repository, 5, host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "Title", Draft: true})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if len(requests) != 2 || requests[0] != http.MethodGet || requests[1] != http.MethodPut {
+		t.Errorf("Got requests %v, wanted [GET PUT]", requests)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var authServices = []string{"jira", "gitlab", "github", "bitbucket"}
+
+func Auth() *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage your API tokens",
+		Long:  `Store, remove and check the API tokens jitlab uses, keeping them out of the config file if you configured a credential store`,
+	}
+
+	authCmd.AddCommand(authLogin())
+	authCmd.AddCommand(authLogout())
+	authCmd.AddCommand(authStatus())
+
+	return authCmd
+}
+
+func authLogin() *cobra.Command {
+	loginCmd := &cobra.Command{
+		Use:       "login <service>",
+		Short:     "Store the API token of a service",
+		Long:      fmt.Sprintf("Store the API token of a service (%s) in the configured credential store", strings.Join(authServices, ", ")),
+		Args:      authServiceArg,
+		ValidArgs: authServices,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			key := args[0] + ".token"
+
+			token, err := questionService.AskForSecret(fmt.Sprintf("Paste your %s token:", args[0]))
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			if credentialStore == nil {
+				if err := writeConfig(viper.ConfigFileUsed(), map[string]interface{}{profileKey(key): token}); err != nil {
+					log.Fatalln(err)
+				}
+				log.Printf("Token saved in %s. Set \"credentials.store\" to keep it out of the config file", viper.ConfigFileUsed())
+				return
+			}
+
+			if err := credentialStore.Set(ctx, profileKey(key), token); err != nil {
+				log.Fatalln(err)
+			}
+			log.Printf("Token saved in the %s store", viper.GetString("credentials.store"))
+
+			if viper.GetString(key) != "" {
+				log.Printf("You can now remove \"%s\" from %s", key, viper.ConfigFileUsed())
+			}
+		},
+	}
+
+	return loginCmd
+}
+
+func authLogout() *cobra.Command {
+	logoutCmd := &cobra.Command{
+		Use:       "logout <service>",
+		Short:     "Remove the API token of a service",
+		Long:      fmt.Sprintf("Remove the API token of a service (%s) from the configured credential store", strings.Join(authServices, ", ")),
+		Args:      authServiceArg,
+		ValidArgs: authServices,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			key := args[0] + ".token"
+
+			if credentialStore == nil {
+				if err := writeConfig(viper.ConfigFileUsed(), map[string]interface{}{profileKey(key): nil}); err != nil {
+					log.Fatalln(err)
+				}
+				log.Printf("Token removed from %s", viper.ConfigFileUsed())
+				return
+			}
+
+			if err := credentialStore.Delete(ctx, profileKey(key)); err != nil {
+				log.Fatalln(err)
+			}
+			log.Printf("Token removed from the %s store", viper.GetString("credentials.store"))
+		},
+	}
+
+	return logoutCmd
+}
+
+func authStatus() *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show where each API token comes from",
+		Long:  `Show, for every service, whether a token is set and where jitlab reads it from`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			for _, service := range authServices {
+				_, source := findSecret(ctx, service+".token")
+				if source == "" {
+					source = "not set"
+				}
+				fmt.Printf("%-10s %s\n", service, source)
+			}
+		},
+	}
+
+	return statusCmd
+}
+
+func authServiceArg(cmd *cobra.Command, args []string) error {
+	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		return err
+	}
+
+	return cobra.OnlyValidArgs(cmd, args)
+}
@@ -12,14 +12,17 @@ func Commits() *cobra.Command {
 		Short: "Commit your changes",
 		Long:  `Commit your changes with a commit message following a pattern (for example, you may want to include a Jira ticket reference)`,
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
 			commitMessage, _ := cmd.Flags().GetString("message")
 
-			branch, err := gitService.GetCurrentBranch()
+			branch, err := gitService.GetCurrentBranch(ctx)
 			if err != nil {
 				log.Fatalln(err)
 			}
 
-			gitService.Commit(branch, commitMessage)
+			gitService.Commit(ctx, branch, commitMessage)
 		},
 	}
 
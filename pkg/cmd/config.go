@@ -1,7 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boh717/jitlab/pkg/credential"
+	"github.com/boh717/jitlab/pkg/jira"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -13,19 +24,35 @@ func Config() *cobra.Command {
 		Short: "Configure Jitlab on first run",
 		Long:  `Run this command the first time you run Jitlab to configure board and columns`,
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
 			log.Println("Configuring jitlab...")
-			boards, err := jiraService.GetBoards()
+			boardName, _ := cmd.Flags().GetString("board-name")
+			repo, _ := cmd.Flags().GetBool("repo")
+
+			jiraService, err := getJiraService(ctx)
 			if err != nil {
 				log.Fatalln(err)
 			}
+
+			boards, err := jiraService.GetBoards(ctx, boardName)
+			if err != nil {
+				log.Fatalln(explain(err))
+			}
+
+			if len(boards) == 0 {
+				log.Fatalf("Your search \"%s\" didn't match any board", boardName)
+			}
+
 			chosenBoard, err := questionService.AskForBoard(boards)
 			if err != nil {
 				log.Fatalln(err)
 			}
 
-			columns, err := jiraService.GetBoardColumns(chosenBoard)
+			columns, err := jiraService.GetBoardColumns(ctx, chosenBoard)
 			if err != nil {
-				log.Fatalln(err)
+				log.Fatalln(explain(err))
 			}
 
 			chosenColumns, err := questionService.AskForColumns(columns)
@@ -33,15 +60,206 @@ func Config() *cobra.Command {
 				log.Fatalln(err)
 			}
 
-			viper.Set("board", chosenBoard)
-			viper.Set("columns", chosenColumns)
+			// Columns aren't statuses: a "Doing" column may hold "In Progress".
+			var boardStatuses []jira.Status
+			statuses, err := jiraService.GetStatuses(ctx)
+			if err != nil {
+				log.Printf("Could not read the statuses of the board, you'll have to type them: %v", explain(err))
+			} else {
+				boardStatuses = jira.BoardStatuses(columns, statuses)
+			}
+
+			branchCreatedStatus, err := questionService.AskForStatus("Which status should an issue move to when you start working on it?", boardStatuses)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			mrOpenedStatus, err := questionService.AskForStatus("Which status should an issue move to when you open a merge request?", boardStatuses)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			values := map[string]interface{}{
+				"board":                     chosenBoard,
+				"columns":                   chosenColumns,
+				"transitions.branchCreated": branchCreatedStatus,
+				"transitions.mrOpened":      mrOpenedStatus}
 
-			if err := viper.WriteConfigAs(viper.ConfigFileUsed()); err != nil {
+			if repo {
+				if err := writeRepositoryConfig(ctx, values); err != nil {
+					log.Fatalln(err)
+				}
+				log.Println("Board saved in the settings of the repository")
+				return
+			}
+
+			profileValues := map[string]interface{}{}
+			for key, value := range values {
+				profileValues[profileKey(key)] = value
+			}
+			if err := writeConfig(viper.ConfigFileUsed(), profileValues); err != nil {
 				log.Fatalln(err)
 			}
 		},
 	}
 
+	var boardName string
+	var repo bool
+
+	configCmd.Flags().StringVar(&boardName, "board-name", "", "Only boards whose name contains this string")
+	configCmd.Flags().BoolVar(&repo, "repo", false, "Save the board for the current repository only")
+
+	configCmd.AddCommand(configShow())
+
 	return configCmd
 
 }
+
+func configShow() *cobra.Command {
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration",
+		Long:  `Show every setting jitlab uses, once the config file, the profile, the repository settings and the environment are merged, and where each value comes from`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			sources := loadConfigSources(ctx)
+
+			keys := viper.AllKeys()
+			for _, service := range authServices {
+				keys = append(keys, service+".token")
+			}
+			// Settings only found in the environment aren't known to viper.AllKeys.
+			for _, env := range os.Environ() {
+				if name := strings.SplitN(env, "=", 2)[0]; strings.HasPrefix(name, "JITLAB_") {
+					keys = append(keys, strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, "JITLAB_"), "_", ".")))
+				}
+			}
+			sort.Strings(keys)
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			shown := map[string]bool{}
+			for _, key := range keys {
+				if shown[key] || strings.HasPrefix(key, profilesKey+".") {
+					continue
+				}
+				shown[key] = true
+
+				if strings.HasSuffix(key, ".token") {
+					if secret, source := findSecret(ctx, key); secret != "" {
+						fmt.Fprintf(writer, "%s\t%s\t%s\n", sources.name(key), "********", source)
+					}
+					continue
+				}
+
+				fmt.Fprintf(writer, "%s\t%s\t%s\n", sources.name(key), formatConfigValue(viper.Get(key)), sources.of(key))
+			}
+			writer.Flush()
+		},
+	}
+
+	return showCmd
+}
+
+// Keys without a default may only be set in the environment.
+var camelCaseKeys = []string{"branchPrefix", "branchSuffix", "keyCommitSeparator", "mrTemplate", "jira.apiVersion", "transitions.branchCreated", "transitions.mrOpened", "board.location.projectKey"}
+
+// configSources tells which config layer a merged value comes from.
+type configSources struct {
+	file       *viper.Viper
+	profile    *viper.Viper
+	repository *viper.Viper
+	// filePath and repositoryPath are where the layers were read from.
+	filePath       string
+	repositoryPath string
+	// names maps the lowercase keys of viper to the way they are written.
+	names map[string]string
+}
+
+func loadConfigSources(ctx context.Context) configSources {
+	sources := configSources{file: viper.New(), profile: viper.New(), repository: viper.New(), filePath: viper.ConfigFileUsed(), names: map[string]string{}}
+	addKeyNames(sources.names, "", configDefaults)
+	for _, name := range camelCaseKeys {
+		sources.names[strings.ToLower(name)] = name
+	}
+
+	sources.file.SetConfigFile(sources.filePath)
+	if err := sources.file.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		log.Printf("Could not read %s: %v", sources.filePath, err)
+	}
+
+	// Viper lowercases keys, so their spelling is read from the file itself.
+	fileConfig := map[string]interface{}{}
+	if content, err := ioutil.ReadFile(sources.filePath); err == nil && json.Unmarshal(content, &fileConfig) == nil {
+		addKeyNames(sources.names, "", fileConfig)
+	}
+
+	if profileName != "" {
+		profileConfig := sources.file.GetStringMap(profilesKey + "." + profileName)
+		sources.profile.MergeConfigMap(profileConfig)
+		if rawProfiles, ok := fileConfig[profilesKey].(map[string]interface{}); ok {
+			if rawProfile, ok := rawProfiles[profileName].(map[string]interface{}); ok {
+				addKeyNames(sources.names, "", rawProfile)
+			}
+		}
+	}
+
+	if repository, err := readRepository(ctx); err == nil {
+		// Before merging, which lowercases the keys.
+		addKeyNames(sources.names, "", repository.Config)
+		sources.repository.MergeConfigMap(repository.Config)
+		sources.repositoryPath, _ = existingRepositoryFile(ctx)
+	}
+
+	return sources
+}
+
+func addKeyNames(names map[string]string, prefix string, config map[string]interface{}) {
+	for key, value := range config {
+		name := prefix + key
+		names[strings.ToLower(name)] = name
+
+		if child, ok := value.(map[string]interface{}); ok {
+			addKeyNames(names, name+".", child)
+		}
+	}
+}
+
+func (s configSources) name(key string) string {
+	if name, found := s.names[key]; found {
+		return name
+	}
+
+	return key
+}
+
+func (s configSources) of(key string) string {
+	if _, found := os.LookupEnv(credential.EnvName(key)); found {
+		return fmt.Sprintf("environment (%s)", credential.EnvName(key))
+	}
+
+	switch {
+	case s.repository.IsSet(key):
+		return s.repositoryPath
+	case s.profile.IsSet(key):
+		return fmt.Sprintf("profile %s", profileName)
+	case s.file.IsSet(key):
+		return s.filePath
+	default:
+		return "default"
+	}
+}
+
+func formatConfigValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(content)
+}
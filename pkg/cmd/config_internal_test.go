@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+)
+
+func newConfigSources(t *testing.T, file, profile, repository map[string]interface{}) configSources {
+	sources := configSources{file: viper.New(), profile: viper.New(), repository: viper.New(), filePath: "/home/me/.jitlab.json", repositoryPath: "/repo/.git/jitlab.json", names: map[string]string{}}
+
+	for layer, config := range map[*viper.Viper]map[string]interface{}{sources.file: file, sources.profile: profile, sources.repository: repository} {
+		addKeyNames(sources.names, "", config)
+		if err := layer.MergeConfigMap(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return sources
+}
+
+func TestConfigSourcesOf(t *testing.T) {
+	file := map[string]interface{}{"branchPrefix": "feature/", "jira": map[string]interface{}{"baseurl": "https://jira.example.com"}}
+	profile := map[string]interface{}{"jira": map[string]interface{}{"baseurl": "https://client.example.com"}}
+	repository := map[string]interface{}{"branchPrefix": "fix/"}
+
+	tests := map[string]struct {
+		key  string
+		env  string
+		want string
+	}{
+		"Default":               {"keycommitseparator", "", "default"},
+		"Profile over the file": {"jira.baseurl", "", "profile client"},
+		"Repository override":   {"branchprefix", "", "/repo/.git/jitlab.json"},
+		"Environment":           {"branchprefix", "bugfix/", "environment (JITLAB_BRANCHPREFIX)"},
+	}
+
+	profileName = "client"
+	defer func() { profileName = "" }()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.env != "" {
+				os.Setenv("JITLAB_BRANCHPREFIX", tc.env)
+				defer os.Unsetenv("JITLAB_BRANCHPREFIX")
+			}
+
+			result := newConfigSources(t, file, profile, repository).of(tc.key)
+
+			if result != tc.want {
+				t.Errorf("Wanted '%s', got '%s'", tc.want, result)
+			}
+		})
+	}
+
+	t.Run("Only in the file", func(t *testing.T) {
+		result := newConfigSources(t, file, nil, nil).of("branchprefix")
+
+		if result != "/home/me/.jitlab.json" {
+			t.Errorf("Wanted '/home/me/.jitlab.json', got '%s'", result)
+		}
+	})
+}
+
+func TestConfigSourcesName(t *testing.T) {
+	sources := newConfigSources(t, map[string]interface{}{"branchPrefix": "feature/", "transitions": map[string]interface{}{"mrOpened": "In Review"}}, nil, nil)
+
+	tests := map[string]struct {
+		key  string
+		want string
+	}{
+		"Top level key": {"branchprefix", "branchPrefix"},
+		"Nested key":    {"transitions.mropened", "transitions.mrOpened"},
+		"Unknown key":   {"jira.baseurl", "jira.baseurl"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := sources.name(tc.key)
+
+			if result != tc.want {
+				t.Errorf("Wanted '%s', got '%s'", tc.want, result)
+			}
+		})
+	}
+}
+
+func TestSetNested(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]interface{}
+		key    []string
+		want   map[string]interface{}
+	}{
+		"New top level key": {
+			map[string]interface{}{},
+			[]string{"branchPrefix"},
+			map[string]interface{}{"branchPrefix": "value"},
+		},
+		"New nested key": {
+			map[string]interface{}{"jira": map[string]interface{}{"baseurl": "https://jira.example.com"}},
+			[]string{"jira", "token"},
+			map[string]interface{}{"jira": map[string]interface{}{"baseurl": "https://jira.example.com", "token": "value"}},
+		},
+		"Existing key in another case": {
+			map[string]interface{}{"branchPrefix": "feature/"},
+			[]string{"branchprefix"},
+			map[string]interface{}{"branchPrefix": "value"},
+		},
+		"Value replaced by a block": {
+			map[string]interface{}{"board": "old"},
+			[]string{"board", "id"},
+			map[string]interface{}{"board": map[string]interface{}{"id": "value"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			setNested(tc.config, tc.key, "value")
+
+			if !cmp.Equal(tc.config, tc.want) {
+				t.Errorf("Wanted '%+v', got '%+v'", tc.want, tc.config)
+			}
+		})
+	}
+}
+
+func TestWriteConfig(t *testing.T) {
+	tests := map[string]struct {
+		existing string
+		values   map[string]interface{}
+		want     map[string]interface{}
+	}{
+		"New file": {
+			"",
+			map[string]interface{}{"jira.token": "secret"},
+			map[string]interface{}{"jira": map[string]interface{}{"token": "secret"}},
+		},
+		"Other keys kept": {
+			`{"branchPrefix": "feature/", "jira": {"baseurl": "https://jira.example.com"}}`,
+			map[string]interface{}{"jira.token": "secret"},
+			map[string]interface{}{"branchPrefix": "feature/", "jira": map[string]interface{}{"baseurl": "https://jira.example.com", "token": "secret"}},
+		},
+		"Key removed": {
+			`{"jira": {"baseurl": "https://jira.example.com", "Token": "secret"}}`,
+			map[string]interface{}{"jira.token": nil, "gitlab.token": nil},
+			map[string]interface{}{"jira": map[string]interface{}{"baseurl": "https://jira.example.com"}},
+		},
+		"Profile key": {
+			`{"profiles": {"client": {}}}`,
+			map[string]interface{}{"profiles.client.gitlab.token": "secret"},
+			map[string]interface{}{"profiles": map[string]interface{}{"client": map[string]interface{}{"gitlab": map[string]interface{}{"token": "secret"}}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "jitlab")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, ".jitlab.json")
+			if tc.existing != "" {
+				writeFile(t, file, tc.existing)
+			}
+
+			if err := writeConfig(file, tc.values); err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := map[string]interface{}{}
+			if err := json.Unmarshal(content, &result); err != nil {
+				t.Fatalf("Wrote invalid JSON '%s': %v", content, err)
+			}
+
+			if !cmp.Equal(result, tc.want) {
+				t.Errorf("Wanted '%+v', got '%+v'", tc.want, result)
+			}
+
+			if info, _ := os.Stat(file); info.Mode().Perm() != 0600 {
+				t.Errorf("Wanted mode 0600, got %v", info.Mode().Perm())
+			}
+		})
+	}
+}
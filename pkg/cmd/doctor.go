@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/credential"
+	"github.com/boh717/jitlab/pkg/git"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/jira"
+	"github.com/boh717/jitlab/pkg/rest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// groupChecker is implemented by the host services that work within a group.
+type groupChecker interface {
+	GetGroup(ctx context.Context) (string, error)
+}
+
+type doctorReport struct {
+	failures int
+}
+
+func (r *doctorReport) pass(name string, detail string) {
+	fmt.Printf("[PASS] %s: %s\n", name, detail)
+}
+
+func (r *doctorReport) fail(name string, err error, hint string) {
+	r.failures++
+	fmt.Printf("[FAIL] %s: %v\n", name, err)
+	if hint != "" {
+		fmt.Printf("       hint: %s\n", hint)
+	}
+}
+
+func (r *doctorReport) skip(name string, reason string) {
+	fmt.Printf("[SKIP] %s: %s\n", name, reason)
+}
+
+func Doctor() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check your configuration and connectivity",
+		Long:  `Run this command when something doesn't work: it checks the configuration file, the credentials, the board and the current repository and tells you how to fix what's wrong`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			setupViper()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			report := &doctorReport{}
+			runDoctor(ctx, report)
+
+			if report.failures > 0 {
+				fmt.Printf("\n%d check(s) failed\n", report.failures)
+				os.Exit(1)
+			}
+			fmt.Println("\nEverything looks good")
+		},
+	}
+
+	return doctorCmd
+}
+
+func runDoctor(ctx context.Context, report *doctorReport) {
+	if !checkConfigFile(report) {
+		return
+	}
+
+	initGitService()
+
+	if err := applyProfile(ctx); err != nil {
+		report.fail("Profile", err, "add it under \"profiles\" in your config file, run \"jitlab setup --profile "+profileName+"\" or pick another one with --profile")
+		report.skip("Other checks", "they need a valid profile")
+		return
+	}
+	if profileName != "" {
+		report.pass("Profile", profileName)
+	}
+
+	// Invalid repository settings are reported by the repository check below.
+	applyRepositoryConfig(ctx)
+
+	if err := initServices(); err != nil {
+		report.fail("Credential store", err, credentialStoreHint())
+	} else if credentialStore != nil {
+		report.pass("Credential store", viper.GetString("credentials.store"))
+	}
+
+	repository, repoErr := readRepository(ctx)
+
+	hosts := []string{viper.GetString("host")}
+	if repoErr == nil && repository.HostName() != hosts[0] {
+		hosts = append(hosts, repository.HostName())
+	}
+
+	jiraReachable := checkBaseUrl(report, "jira.baseurl")
+	if jiraReachable {
+		jiraReachable = checkJiraAuth(ctx, report)
+	}
+
+	for _, hostName := range hosts {
+		if checkBaseUrl(report, hostName+".baseurl") && checkHostAuth(ctx, report, hostName) {
+			checkGroup(ctx, report, hostName)
+		}
+	}
+
+	if jiraReachable {
+		checkBoard(ctx, report)
+	} else {
+		report.skip("Jira board", "Jira is not reachable")
+	}
+
+	if repoErr != nil {
+		if errors.Is(repoErr, errNoRepository) {
+			report.skip("Repository", fmt.Sprintf("%v, \"jitlab mr\" will find the project", repoErr))
+		} else {
+			report.fail("Repository", repoErr, "run \"jitlab init\" again to recreate the repository settings")
+		}
+		report.skip("Git remote", "no repository to compare with")
+		return
+	}
+	report.pass("Repository", repository.String())
+
+	checkRemote(ctx, report, repository)
+}
+
+func checkConfigFile(report *doctorReport) bool {
+	err := viper.ReadInConfig()
+	switch {
+	case err == nil:
+		report.pass("Config file", viper.ConfigFileUsed())
+		return true
+	case os.IsNotExist(err) && hasEnvConfig():
+		report.pass("Config file", "not found, using environment variables only")
+		return true
+	case os.IsNotExist(err):
+		report.fail("Config file", fmt.Errorf("%s not found", viper.ConfigFileUsed()), "run \"jitlab setup\" to create it")
+	default:
+		report.fail("Config file", fmt.Errorf("%s can't be read: %v", viper.ConfigFileUsed(), err), "fix the syntax error or run \"jitlab setup\" again")
+	}
+
+	report.skip("Other checks", "they need a valid configuration")
+	return false
+}
+
+func checkBaseUrl(report *doctorReport, key string) bool {
+	name := fmt.Sprintf("URL %s", key)
+	value := viper.GetString(key)
+
+	if value == "" {
+		report.fail(name, fmt.Errorf("not set"), fmt.Sprintf("set \"%s\" in your config file or run \"jitlab setup\"", key))
+		return false
+	}
+
+	if err := validateBaseUrl(value); err != nil {
+		report.fail(name, fmt.Errorf("%s is not valid", value), fmt.Sprintf("set \"%s\" to a full URL, such as https://example.com", key))
+		return false
+	}
+
+	report.pass(name, value)
+	return true
+}
+
+func checkJiraAuth(ctx context.Context, report *doctorReport) bool {
+	jiraService, err := getJiraService(ctx)
+	if err != nil {
+		report.fail("Jira authentication", err, jiraServiceHint())
+		return false
+	}
+
+	user, err := jiraService.GetCurrentUser(ctx)
+	if err != nil {
+		report.fail("Jira authentication", explain(err), authHint(err, "jira.baseurl", "check jira.username and jira.token, or run \"jitlab auth login jira\""))
+		return false
+	}
+
+	report.pass("Jira authentication", fmt.Sprintf("authenticated as %s", user.DisplayName))
+	return true
+}
+
+func checkHostAuth(ctx context.Context, report *doctorReport, hostName string) bool {
+	name := fmt.Sprintf("%s authentication", hostName)
+
+	service, err := hostService(ctx, hostName)
+	if err != nil {
+		report.fail(name, err, "set \"host\" to gitlab, github or bitbucket")
+		return false
+	}
+
+	username, err := service.CheckAuth(ctx)
+	if err != nil {
+		report.fail(name, explain(err), authHint(err, hostName+".baseurl", fmt.Sprintf("check %s.token, or run \"jitlab auth login %s\"", hostName, hostName)))
+		return false
+	}
+
+	if username == "" {
+		report.pass(name, "token accepted")
+	} else {
+		report.pass(name, fmt.Sprintf("authenticated as %s", username))
+	}
+	return true
+}
+
+// Only a rejected token calls for checking the credentials.
+func authHint(err error, urlKey string, credentialHint string) string {
+	var apiError *rest.ApiError
+	if !errors.As(err, &apiError) {
+		return fmt.Sprintf("check %s and your network connection", urlKey)
+	}
+
+	if apiError.IsUnauthorized() {
+		return credentialHint
+	}
+
+	return fmt.Sprintf("check %s points to the API of the service", urlKey)
+}
+
+func checkGroup(ctx context.Context, report *doctorReport, hostName string) {
+	service, err := hostService(ctx, hostName)
+	if err != nil {
+		return
+	}
+
+	checker, ok := service.(groupChecker)
+	if !ok {
+		return
+	}
+
+	name := fmt.Sprintf("%s group", hostName)
+	if viper.GetString(hostName+".groupid") == "" {
+		report.fail(name, fmt.Errorf("%s.groupid is not set", hostName), "set it to the ID of the group your projects belong to")
+		return
+	}
+
+	group, err := checker.GetGroup(ctx)
+	if err != nil {
+		report.fail(name, explain(err), fmt.Sprintf("check %s.groupid and that your token can access the group", hostName))
+		return
+	}
+
+	report.pass(name, group)
+}
+
+func checkBoard(ctx context.Context, report *doctorReport) {
+	if !viper.IsSet("board.id") {
+		report.fail("Jira board", fmt.Errorf("no board configured"), "run \"jitlab config\" to choose one")
+		return
+	}
+
+	// Read key by key, as viper.UnmarshalKey misses the environment.
+	var board jira.Board
+	board.ID = viper.GetInt("board.id")
+	board.Name = viper.GetString("board.name")
+	board.Type = viper.GetString("board.type")
+	board.Location.ProjectKey = viper.GetString("board.location.projectkey")
+
+	jiraService, err := getJiraService(ctx)
+	if err != nil {
+		report.fail("Jira board", err, jiraServiceHint())
+		return
+	}
+
+	if _, err := jiraService.GetBoardColumns(ctx, board); err != nil {
+		report.fail("Jira board", explain(err), "run \"jitlab config\" to choose the board again")
+		return
+	}
+
+	if board.Name == "" {
+		report.pass("Jira board", fmt.Sprintf("board %d", board.ID))
+		return
+	}
+	report.pass("Jira board", board.Name)
+}
+
+func credentialStoreHint() string {
+	switch strings.ToLower(viper.GetString("credentials.store")) {
+	case credential.GpgStoreType, credential.AgeStoreType:
+		return "set credentials.recipient to the key the credential file is encrypted for"
+	default:
+		return fmt.Sprintf("set credentials.store to %s, %s, %s or %s", credential.ConfigStoreType, credential.KeyringStoreType, credential.GpgStoreType, credential.AgeStoreType)
+	}
+}
+
+func jiraServiceHint() string {
+	if _, err := jira.NewAuth(viper.GetString("jira.auth"), "", ""); err != nil {
+		return fmt.Sprintf("set jira.auth to %s or %s", jira.BasicAuthType, jira.BearerAuthType)
+	}
+
+	return "check jira.baseurl in your config file"
+}
+
+func checkRemote(ctx context.Context, report *doctorReport, repository host.Repository) {
+	remoteUrl, err := gitService.GetRemoteUrl(ctx, "origin")
+	if err != nil {
+		report.fail("Git remote", err, "run the command inside a git repository with an \"origin\" remote")
+		return
+	}
+
+	if !remoteMatches(remoteUrl, repository) {
+		report.fail("Git remote", fmt.Errorf("origin %s doesn't match %s", remoteUrl, repository.String()), "run \"jitlab init\" again to pick the right project")
+		return
+	}
+
+	report.pass("Git remote", remoteUrl)
+}
+
+// Repositories initialized before FullPath was recorded are matched by name.
+func remoteMatches(remoteUrl string, repository host.Repository) bool {
+	remotePath := strings.ToLower(git.RemotePath(remoteUrl))
+
+	if repository.FullPath != "" {
+		fullPath := strings.ToLower(strings.Trim(repository.FullPath, "/"))
+		return remotePath == fullPath || strings.HasSuffix(remotePath, "/"+fullPath)
+	}
+
+	return strings.HasSuffix("/"+remotePath, "/"+strings.ToLower(repository.Path))
+}
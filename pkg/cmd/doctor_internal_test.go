@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+func TestAuthHint(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"Unauthorized":       {&rest.ApiError{StatusCode: 401}, "check the token"},
+		"Forbidden, wrapped": {fmt.Errorf("reading user: %w", &rest.ApiError{StatusCode: 403}), "check the token"},
+		"Not found":          {&rest.ApiError{StatusCode: 404}, "check jira.baseurl points to the API of the service"},
+		"Connection refused": {errors.New("dial tcp 127.0.0.1:9: connect: connection refused"), "check jira.baseurl and your network connection"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := authHint(tc.err, "jira.baseurl", "check the token")
+
+			if result != tc.want {
+				t.Errorf("Wanted '%s', got '%s'", tc.want, result)
+			}
+		})
+	}
+}
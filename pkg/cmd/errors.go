@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+// explain tells the user how to fix API errors.
+func explain(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.New("timed out: try again or raise --timeout")
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return errors.New("interrupted")
+	}
+
+	var apiError *rest.ApiError
+	if !errors.As(err, &apiError) {
+		return err
+	}
+
+	host := apiError.URL
+	if parsedUrl, parseErr := url.Parse(apiError.URL); parseErr == nil && parsedUrl.Host != "" {
+		host = parsedUrl.Host
+	}
+
+	switch {
+	case apiError.IsUnauthorized():
+		return fmt.Errorf("%s rejected your credentials (%d): check the token in your config file and its permissions", host, apiError.StatusCode)
+	case apiError.IsNotFound():
+		return fmt.Errorf("%s couldn't find the requested resource: check it exists and you have access to it (%v)", host, apiError)
+	case apiError.IsRateLimited():
+		return fmt.Errorf("%s is rate limiting your requests: wait a bit and try again", host)
+	default:
+		return err
+	}
+}
@@ -1,13 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
-	"io/ioutil"
 	"log"
-	"os"
-	"path"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func InitRepo() *cobra.Command {
@@ -16,39 +13,49 @@ func InitRepo() *cobra.Command {
 		Short: "Configure your repository",
 		Long:  `Run this command in every git repo you want to use Jitlab`,
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
 			log.Println("Init repo...")
+			hostName, _ := cmd.Flags().GetString("host")
+			if !cmd.Flags().Changed("host") {
+				hostName = viper.GetString("host")
+			}
 
-			currentPath, err := os.Getwd()
+			service, err := hostService(ctx, hostName)
 			if err != nil {
 				log.Fatalln(err)
 			}
-			currentDir := path.Base(currentPath)
 
-			repositories, err := gitlabService.SearchProject(currentDir)
+			if _, err := gitService.GetRepositoryRoot(ctx); err != nil {
+				log.Fatalln("Run this command inside a git repository", err)
+			}
+
+			chosenRepo, err := findRepository(ctx, service)
 			if err != nil {
 				log.Fatalln(err)
 			}
+			log.Printf("Found project %s", chosenRepo)
 
-			if len(repositories) == 0 {
-				log.Fatalf("Your search \"%s\" didn't match any project", currentDir)
+			if err := migrateRepository(ctx); err != nil {
+				log.Printf("Could not migrate %s: %v", legacyRepositoryFile, err)
 			}
 
-			var chosenRepo = repositories[0]
-			if len(repositories) > 1 {
-				chosenRepo, err = questionService.AskForRepository(repositories)
-				if err != nil {
-					log.Fatalln(err)
-				}
+			chosenRepo.Profile = profileName
+			if existing, err := readRepository(ctx); err == nil {
+				chosenRepo.Config = existing.Config
 			}
 
-			file, _ := json.MarshalIndent(chosenRepo, "", " ")
-
-			if err := ioutil.WriteFile(".repo", file, 0644); err != nil {
+			if err := writeRepository(ctx, chosenRepo); err != nil {
 				log.Fatalln(err)
 			}
 
 		},
 	}
 
+	var hostName string
+
+	initCmd.Flags().StringVar(&hostName, "host", "", "Code host of the repository: gitlab, github or bitbucket (default is \"host\" from config, or gitlab)")
+
 	return initCmd
 }
@@ -1,68 +1,194 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"log"
 
-	"github.com/boh717/jitlab/pkg/gitlab"
+	"github.com/boh717/jitlab/pkg/description"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/jira"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// gitlabOnlyFlags are merge options that only GitLab takes per merge request.
+var gitlabOnlyFlags = []string{"remove-source-branch", "squash"}
+
 func MergeRequest() *cobra.Command {
 	mrCmd := &cobra.Command{
 		Use:   "mr",
 		Short: "Create a new merge request",
 		Long:  `Run this command to create a new merge request using target branch and (limited) options of your choice`,
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
 			log.Println("Creating new merge request...")
 			targetBranch, _ := cmd.Flags().GetString("target-branch")
 			removeSourceBranch, _ := cmd.Flags().GetBool("remove-source-branch")
 			squash, _ := cmd.Flags().GetBool("squash")
+			mrDescription, _ := cmd.Flags().GetString("description")
+			labels, _ := cmd.Flags().GetStringSlice("label")
+			assigneeIDs, _ := cmd.Flags().GetIntSlice("assignee-id")
+			reviewerIDs, _ := cmd.Flags().GetIntSlice("reviewer-id")
+			milestoneID, _ := cmd.Flags().GetInt("milestone-id")
+			draft, _ := cmd.Flags().GetBool("draft")
+			update, _ := cmd.Flags().GetBool("update")
 
-			branch, err := gitService.GetCurrentBranch()
+			branch, err := gitService.GetCurrentBranch(ctx)
 			if err != nil {
 				log.Fatalln(err)
 			}
 
-			_, pushErr := gitService.Push(branch)
+			_, pushErr := gitService.Push(ctx, branch)
 			if pushErr != nil {
 				log.Fatalln(pushErr)
 			}
 
-			var currentRepository gitlab.Repository
+			currentRepository, err := loadRepository(ctx)
+			if err != nil {
+				log.Fatalln("Error finding the project of the repository", err)
+			}
 
-			file, err := ioutil.ReadFile(".repo")
+			service, err := hostService(ctx, currentRepository.HostName())
 			if err != nil {
-				log.Fatalln("Error reading repository file \".repo\"", err)
+				log.Fatalln(err)
 			}
 
-			json.Unmarshal(file, &currentRepository)
-			projectId := fmt.Sprintf("%d", currentRepository.ID)
+			if currentRepository.HostName() != host.Gitlab {
+				for _, flag := range gitlabOnlyFlags {
+					if cmd.Flags().Changed(flag) {
+						log.Printf("Ignoring --%s, %s doesn't support it", flag, currentRepository.HostName())
+					}
+				}
+			}
 
 			title, err := gitService.CreateTitleFromBranch(branch)
 			if err != nil {
 				log.Fatalln("Error creating title from branch", err)
 			}
 
-			resp, err := gitlabService.CreateMergeRequest(projectId, branch, targetBranch, title, removeSourceBranch, squash)
+			issueKey := gitService.GetIssueKey(branch)
+
+			if !cmd.Flags().Changed("description") {
+				mrDescription, err = buildDescription(ctx, branch, targetBranch, issueKey)
+				if err != nil {
+					log.Fatalln("Error creating description", err)
+				}
+			}
+
+			options := host.MergeRequestOptions{
+				SourceBranch:       branch,
+				TargetBranch:       targetBranch,
+				Title:              title,
+				Description:        mrDescription,
+				Labels:             labels,
+				AssigneeIDs:        assigneeIDs,
+				ReviewerIDs:        reviewerIDs,
+				MilestoneID:        milestoneID,
+				RemoveSourceBranch: removeSourceBranch,
+				Squash:             squash,
+				Draft:              draft}
+
+			existing, found, err := service.FindMergeRequest(ctx, currentRepository, branch)
 			if err != nil {
-				log.Fatalln("Error creating merge request", err)
+				log.Fatalln("Error looking for existing merge request", explain(err))
+			}
+
+			if found {
+				if !update {
+					log.Printf("Merge request already exists: %s (use --update to update it)", existing.Url)
+					return
+				}
+
+				if draft && currentRepository.HostName() == host.Github {
+					log.Println("Ignoring --draft, GitHub can't turn an open pull request into a draft")
+				}
+
+				resp, err := service.UpdateMergeRequest(ctx, currentRepository, existing.IID, options)
+				if err != nil {
+					log.Fatalln("Error updating merge request", explain(err))
+				}
+				log.Printf("Merge request updated: %s", resp.Url)
+				return
+			}
+
+			resp, err := service.CreateMergeRequest(ctx, currentRepository, options)
+			if err != nil {
+				log.Fatalln("Error creating merge request", explain(err))
 			}
 			log.Printf("Merge request created: %s", resp.Url)
 
+			if issueKey != "" {
+				if jiraService, err := getJiraService(ctx); err != nil {
+					log.Printf("Could not link merge request to issue %s: %v", issueKey, err)
+				} else if err := jiraService.AddRemoteLink(ctx, issueKey, resp.Url, fmt.Sprintf("Merge request: %s", title)); err != nil {
+					log.Printf("Could not link merge request to issue %s: %v", issueKey, explain(err))
+				}
+			}
+
+			transitionIssue(ctx, issueKey, mrOpenedEvent)
+
 		},
 	}
 
 	var targetBranch string
 	var removeSourceBranch bool
 	var squash bool
+	var mrDescription string
+	var labels []string
+	var assigneeIDs []int
+	var reviewerIDs []int
+	var milestoneID int
+	var draft bool
+	var update bool
 
 	mrCmd.Flags().StringVar(&targetBranch, "target-branch", "master", "Target branch for merge request")
-	mrCmd.Flags().BoolVar(&removeSourceBranch, "remove-source-branch", true, "Remove source branch when merging")
-	mrCmd.Flags().BoolVar(&squash, "squash", true, "Squash commits when merging")
+	mrCmd.Flags().BoolVar(&removeSourceBranch, "remove-source-branch", true, "Remove source branch when merging (GitLab only)")
+	mrCmd.Flags().BoolVar(&squash, "squash", true, "Squash commits when merging (GitLab only)")
+	mrCmd.Flags().StringVarP(&mrDescription, "description", "d", "", "Description of the merge request (default is built from the jira issue and the commits)")
+	mrCmd.Flags().StringSliceVar(&labels, "label", nil, "Labels to add to the merge request (repeat or comma separate)")
+	mrCmd.Flags().IntSliceVar(&assigneeIDs, "assignee-id", nil, "GitLab IDs of the users to assign the merge request to")
+	mrCmd.Flags().IntSliceVar(&reviewerIDs, "reviewer-id", nil, "GitLab IDs of the users to ask for a review")
+	mrCmd.Flags().IntVar(&milestoneID, "milestone-id", 0, "GitLab ID of the milestone of the merge request")
+	mrCmd.Flags().BoolVar(&draft, "draft", false, "Mark the merge request as draft")
+	mrCmd.Flags().BoolVar(&update, "update", false, "Update the open merge request of this branch if there is one")
 
 	return mrCmd
 
 }
+
+func buildDescription(ctx context.Context, branch string, targetBranch string, issueKey string) (string, error) {
+	data := description.Data{Branch: branch, TargetBranch: targetBranch}
+
+	if issueKey != "" {
+		var issue jira.Issue
+		jiraService, err := getJiraService(ctx)
+		if err == nil {
+			issue, err = jiraService.GetIssue(ctx, issueKey)
+		}
+		if err != nil {
+			log.Printf("Could not read issue %s, its details won't be in the description: %v", issueKey, explain(err))
+		} else {
+			data.Issue = description.Issue{
+				Key:         issue.Key,
+				Summary:     issue.Fields.Summary,
+				Description: issue.DescriptionText(),
+				Url:         jiraService.GetIssueUrl(issue.Key)}
+		}
+	}
+
+	commits, err := gitService.GetCommits(ctx, targetBranch)
+	if err != nil {
+		log.Printf("Could not read commits, they won't be in the description: %v", err)
+	}
+	data.Commits = commits
+
+	text := viper.GetString("mrTemplate")
+	if text == "" {
+		text = description.DefaultTemplate
+	}
+
+	return description.Render(text, data)
+}
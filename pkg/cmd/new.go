@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"log"
 
 	"github.com/spf13/cobra"
@@ -13,36 +14,84 @@ func NewTicket() *cobra.Command {
 		Short: "Pick new issue",
 		Long:  `Run this command to pick a new jira issue to work on`,
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
 			log.Println("Picking new issue...")
 			assignedToMe, _ := cmd.Flags().GetBool("me")
+			noAssign, _ := cmd.Flags().GetBool("no-assign")
+			limit, _ := cmd.Flags().GetInt("limit")
+			if !cmd.Flags().Changed("limit") {
+				limit = viper.GetInt("maxIssues")
+			}
 
 			flowType := viper.GetString("board.type")
 			projectKey := viper.GetString("board.location.projectkey")
-			columns := viper.GetStringSlice("columns")
+			columns := configStringSlice("columns")
 
-			issues, err := jiraService.GetIssues(flowType, projectKey, columns, assignedToMe)
+			jiraService, err := getJiraService(ctx)
 			if err != nil {
 				log.Fatalln(err)
 			}
 
+			issues, total, err := jiraService.GetIssues(ctx, flowType, projectKey, columns, assignedToMe, limit)
+			if err != nil {
+				log.Fatalln(explain(err))
+			}
+
+			if len(issues) < total {
+				log.Printf("Showing only %d of %d issues (raise the limit with --limit or \"maxIssues\" in your config)", len(issues), total)
+			}
+
 			chosenIssue, err := questionService.AskForIssue(issues)
 			if err != nil {
 				log.Fatalln(err)
 			}
 
-			newBranch, err := gitService.CreateBranch(chosenIssue)
+			newBranch, err := gitService.CreateBranch(ctx, chosenIssue)
 			if err != nil {
 				log.Fatalln(err)
 			}
 
 			log.Printf("New branch \"%s\" created", newBranch)
+
+			if !assignedToMe && !noAssign && chosenIssue.Fields.Assignee == nil {
+				assignToCurrentUser(ctx, chosenIssue.Key)
+			}
+
+			transitionIssue(ctx, chosenIssue.Key, branchCreatedEvent)
 		},
 	}
 
 	var currentUserFlag bool
+	var noAssignFlag bool
+	var limit int
 
 	newCmd.Flags().BoolVar(&currentUserFlag, "me", false, "Only issues assigned to me")
+	newCmd.Flags().BoolVar(&noAssignFlag, "no-assign", false, "Don't assign unassigned issues to me")
+	newCmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of issues to read from Jira, 0 means no limit (default is \"maxIssues\" from config)")
 
 	return newCmd
 
 }
+
+func assignToCurrentUser(ctx context.Context, issueKey string) {
+	jiraService, err := getJiraService(ctx)
+	if err != nil {
+		log.Printf("Could not assign issue %s: %v", issueKey, err)
+		return
+	}
+
+	currentUser, err := jiraService.GetCurrentUser(ctx)
+	if err != nil {
+		log.Printf("Could not read the current Jira user: %v", explain(err))
+		return
+	}
+
+	if err := jiraService.AssignIssue(ctx, issueKey, currentUser); err != nil {
+		log.Printf("Could not assign issue %s to %s: %v", issueKey, currentUser.DisplayName, explain(err))
+		return
+	}
+
+	log.Printf("Issue %s assigned to %s", issueKey, currentUser.DisplayName)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/boh717/jitlab/pkg/credential"
+	"github.com/spf13/viper"
+)
+
+// profileName is empty when the top level settings are used.
+var profileName string
+
+// activeProfile picks the profile from the --profile flag, the JITLAB_PROFILE
+// environment variable or the repository settings, in this order.
+func activeProfile(ctx context.Context) string {
+	if profileFlag != "" {
+		return profileFlag
+	}
+
+	if value, found := os.LookupEnv(credential.EnvName("profile")); found {
+		return value
+	}
+
+	if repository, err := readRepository(ctx); err == nil {
+		return repository.Profile
+	}
+
+	return ""
+}
+
+// Settings the profile doesn't have are inherited from the top level.
+func applyProfile(ctx context.Context) error {
+	profileName = activeProfile(ctx)
+	if profileName == "" {
+		return nil
+	}
+
+	if !viper.IsSet(profilesKey + "." + profileName) {
+		return fmt.Errorf("profile \"%s\" not found in %s", profileName, viper.ConfigFileUsed())
+	}
+
+	return viper.MergeConfigMap(viper.GetStringMap(profilesKey + "." + profileName))
+}
+
+func profileKey(key string) string {
+	if profileName == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s.%s.%s", profilesKey, profileName, key)
+}
+
+const profilesKey = "profiles"
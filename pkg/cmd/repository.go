@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/git"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/spf13/viper"
+)
+
+const (
+	repositoryFileName   = "jitlab.json"
+	legacyRepositoryFile = ".repo"
+)
+
+// errNoRepository is returned outside of git or before the repository is set up.
+var errNoRepository = errors.New("no jitlab repository")
+
+// Stored in the git directory, the settings are found from any subdirectory
+// and stay out of the working tree.
+func repositoryFile(ctx context.Context) (string, error) {
+	gitDir, err := gitService.GetGitDir(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: not inside a git repository", errNoRepository)
+	}
+
+	return filepath.Join(gitDir, repositoryFileName), nil
+}
+
+// findRepository uses the "origin" remote, then searches the project by name.
+func findRepository(ctx context.Context, service host.HostService) (host.Repository, error) {
+	var search string
+
+	if remoteUrl, err := gitService.GetRemoteUrl(ctx, "origin"); err == nil {
+		remotePath := git.RemotePath(remoteUrl)
+		repository, err := service.GetProject(ctx, remotePath)
+		if err == nil {
+			return repository, nil
+		}
+		log.Printf("Could not find project %s, searching by name instead: %v", remotePath, explain(err))
+		search = path.Base(remotePath)
+	} else {
+		log.Printf("Could not read the origin remote, searching by name instead: %v", err)
+
+		rootPath, err := gitService.GetRepositoryRoot(ctx)
+		if err != nil {
+			return host.Repository{}, err
+		}
+		search = path.Base(rootPath)
+	}
+
+	repositories, err := service.SearchProject(ctx, search)
+	if err != nil {
+		return host.Repository{}, explain(err)
+	}
+
+	switch len(repositories) {
+	case 0:
+		return host.Repository{}, fmt.Errorf("your search \"%s\" didn't match any project", search)
+	case 1:
+		return repositories[0], nil
+	default:
+		return questionService.AskForRepository(repositories)
+	}
+}
+
+// loadRepository finds and saves the project if needed, so init is optional.
+func loadRepository(ctx context.Context) (host.Repository, error) {
+	if err := migrateRepository(ctx); err != nil {
+		log.Printf("Could not migrate %s: %v", legacyRepositoryFile, err)
+	}
+
+	repository, err := readRepository(ctx)
+	if !errors.Is(err, errNoRepository) {
+		return repository, err
+	}
+
+	service, err := hostService(ctx, viper.GetString("host"))
+	if err != nil {
+		return repository, err
+	}
+
+	log.Println("Repository not initialized, looking the project up...")
+	repository, err = findRepository(ctx, service)
+	if err != nil {
+		return repository, err
+	}
+
+	repository.Profile = profileName
+	if err := writeRepository(ctx, repository); err != nil {
+		log.Printf("Could not save project %s, it will be looked up again next time: %v", repository, err)
+	} else {
+		log.Printf("Using project %s, saved for the next runs", repository)
+	}
+
+	return repository, nil
+}
+
+// Until migrated, the ".repo" of older versions is read instead.
+func readRepository(ctx context.Context) (host.Repository, error) {
+	var repository host.Repository
+
+	file, err := existingRepositoryFile(ctx)
+	if err != nil {
+		return repository, err
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return repository, err
+	}
+
+	if err := json.Unmarshal(content, &repository); err != nil {
+		return repository, fmt.Errorf("%s is not valid: %v", file, err)
+	}
+
+	return repository, nil
+}
+
+func existingRepositoryFile(ctx context.Context) (string, error) {
+	file, err := repositoryFile(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(file); err == nil {
+		return file, nil
+	}
+
+	legacyFile, err := legacyRepositoryPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(legacyFile); err == nil {
+		return legacyFile, nil
+	}
+
+	return "", fmt.Errorf("%w: the repository isn't initialized", errNoRepository)
+}
+
+func legacyRepositoryPath(ctx context.Context) (string, error) {
+	root, err := gitService.GetRepositoryRoot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, legacyRepositoryFile), nil
+}
+
+// A ".repo" tracked by git is only copied, leaving the working tree as it is.
+func migrateRepository(ctx context.Context) error {
+	file, err := repositoryFile(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(file); err == nil {
+		return nil
+	}
+
+	legacyFile, err := legacyRepositoryPath(ctx)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(legacyFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(file, content, 0644); err != nil {
+		return err
+	}
+
+	if gitService.IsTracked(ctx, legacyFile) {
+		log.Printf("Copied %s to %s. It's tracked by git, so it was left in place: remove it when your team doesn't need it anymore", legacyFile, file)
+		return nil
+	}
+
+	if err := os.Remove(legacyFile); err != nil {
+		log.Printf("Could not remove %s: %v", legacyFile, err)
+	}
+	log.Printf("Moved %s to %s", legacyFile, file)
+
+	return nil
+}
+
+func writeRepository(ctx context.Context, repository host.Repository) error {
+	file, err := repositoryFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(repository, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, content, 0644)
+}
+
+// The "config" block of the repository overrides the other settings.
+func applyRepositoryConfig(ctx context.Context) error {
+	repository, err := readRepository(ctx)
+	if errors.Is(err, errNoRepository) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(repository.Config) == 0 {
+		return nil
+	}
+
+	return viper.MergeConfigMap(repository.Config)
+}
+
+func writeRepositoryConfig(ctx context.Context, values map[string]interface{}) error {
+	repository, err := readRepository(ctx)
+	if err != nil {
+		return err
+	}
+
+	if repository.Config == nil {
+		repository.Config = map[string]interface{}{}
+	}
+	for key, value := range values {
+		setNested(repository.Config, strings.Split(key, "."), value)
+	}
+
+	return writeRepository(ctx, repository)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/git"
+	"github.com/boh717/jitlab/pkg/gitlab"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/mocks"
+	"github.com/boh717/jitlab/pkg/rest"
+	"github.com/spf13/viper"
+)
+
+// fakeRepository creates a working tree with a git directory and a "sub"
+// directory, moves into dir (relative to the root) and answers the git
+// commands like git would there.
+func fakeRepository(t *testing.T, dir string, tracked bool) string {
+	root, err := ioutil.TempDir("", "jitlab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+
+	for _, path := range []string{".git", "sub"} {
+		if err := os.Mkdir(filepath.Join(root, path), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, dir)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+
+	gitDir, _ := filepath.Rel(filepath.Join(root, dir), filepath.Join(root, ".git"))
+	mocks.RunFakeCommand = func(command string, args ...string) ([]byte, error) {
+		switch args[0] + " " + args[1] {
+		case "rev-parse --git-common-dir":
+			return []byte(gitDir + "\n"), nil
+		case "rev-parse --show-toplevel":
+			return []byte(root + "\n"), nil
+		case "remote get-url":
+			return []byte("git@gitlab.com:group/jitlab.git\n"), nil
+		case "ls-files --error-unmatch":
+			if tracked {
+				return nil, nil
+			}
+			return []byte("error: pathspec did not match any file(s) known to git"), errors.New("exit status 1")
+		}
+		t.Fatalf("Unexpected command %s %v", command, args)
+		return nil, nil
+	}
+	gitService = git.GitServiceImpl{CommandClient: mocks.MockCommandClient{}}
+
+	return root
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateRepository(t *testing.T) {
+	tests := map[string]struct {
+		dir            string
+		tracked        bool
+		migrated       string
+		expected       string
+		expectedLegacy bool
+	}{
+		"Legacy file moved":            {".", false, "", `{"id":1}`, false},
+		"Legacy file tracked by git":   {".", true, "", `{"id":1}`, true},
+		"Legacy file from a subfolder": {"sub", false, "", `{"id":1}`, false},
+		"Already migrated":             {".", false, `{"id":2}`, `{"id":2}`, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := fakeRepository(t, tc.dir, tc.tracked)
+			legacyFile := filepath.Join(root, legacyRepositoryFile)
+			file := filepath.Join(root, ".git", repositoryFileName)
+
+			writeFile(t, legacyFile, `{"id":1}`)
+			if tc.migrated != "" {
+				writeFile(t, file, tc.migrated)
+			}
+
+			if err := migrateRepository(context.Background()); err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			content, err := ioutil.ReadFile(file)
+			if err != nil || string(content) != tc.expected {
+				t.Errorf("Wanted '%s' in %s, got '%s' (%v)", tc.expected, file, content, err)
+			}
+
+			if _, err := os.Stat(legacyFile); tc.expectedLegacy != (err == nil) {
+				t.Errorf("Wanted %s to exist to be %t, got '%v'", legacyFile, tc.expectedLegacy, err)
+			}
+		})
+	}
+}
+
+func TestLoadRepository(t *testing.T) {
+	tests := map[string]struct {
+		dir      string
+		legacy   string
+		migrated string
+		expected host.Repository
+	}{
+		"Initialized":                  {".", "", `{"id":2,"name":"Jitlab"}`, host.Repository{ID: 2, Name: "Jitlab"}},
+		"Legacy file":                  {".", `{"id":1,"name":"Jitlab"}`, "", host.Repository{ID: 1, Name: "Jitlab"}},
+		"Legacy file from a subfolder": {"sub", `{"id":1,"name":"Jitlab"}`, "", host.Repository{ID: 1, Name: "Jitlab"}},
+		"Not initialized":              {"sub", "", "", host.Repository{ID: 42, Name: "Jitlab", Path: "jitlab", FullPath: "group/jitlab", Host: host.Gitlab}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := fakeRepository(t, tc.dir, false)
+			file := filepath.Join(root, ".git", repositoryFileName)
+
+			if tc.legacy != "" {
+				writeFile(t, filepath.Join(root, legacyRepositoryFile), tc.legacy)
+			}
+			if tc.migrated != "" {
+				writeFile(t, file, tc.migrated)
+			}
+
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				if req.URL.EscapedPath() != "/projects/group%2Fjitlab" {
+					t.Fatalf("Unexpected request %s", req.URL)
+				}
+				return mocks.JsonResponse(200, `{"id":42,"name":"Jitlab","path":"jitlab","path_with_namespace":"group/jitlab"}`), nil
+			}
+			viper.Set("host", host.Gitlab)
+			defer viper.Reset()
+			cachedHostServices = map[string]host.HostService{
+				host.Gitlab: gitlab.GitlabServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}}
+
+			result, err := loadRepository(context.Background())
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if result.ID != tc.expected.ID || result.Name != tc.expected.Name || result.FullPath != tc.expected.FullPath {
+				t.Errorf("Wanted repository '%+v', got '%+v'", tc.expected, result)
+			}
+
+			if _, err := os.Stat(file); err != nil {
+				t.Errorf("Wanted the repository to be saved in %s, got '%v'", file, err)
+			}
+		})
+	}
+}
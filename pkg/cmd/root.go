@@ -1,17 +1,28 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path"
 	"regexp"
+	"strings"
+	"syscall"
 
+	"github.com/boh717/jitlab/pkg/bitbucket"
 	"github.com/boh717/jitlab/pkg/command"
+	"github.com/boh717/jitlab/pkg/credential"
 	"github.com/boh717/jitlab/pkg/git"
+	"github.com/boh717/jitlab/pkg/github"
 	"github.com/boh717/jitlab/pkg/gitlab"
+	"github.com/boh717/jitlab/pkg/host"
 	"github.com/boh717/jitlab/pkg/jira"
 	"github.com/boh717/jitlab/pkg/question"
 	"github.com/boh717/jitlab/pkg/rest"
@@ -21,12 +32,16 @@ import (
 )
 
 var (
-	cfgFile         string
-	jiraService     jira.JiraService
-	gitlabService   gitlab.GitlabService
-	gitService      git.GitService
-	questionService question.QuestionService
-	rootCmd         = &cobra.Command{
+	cfgFile     string
+	profileFlag string
+	restClient  rest.RestClient
+	// Built on first use by getJiraService and hostService.
+	cachedJiraService  jira.JiraService
+	cachedHostServices map[string]host.HostService
+	gitService         git.GitService
+	questionService    question.QuestionService
+	credentialStore    credential.Store
+	rootCmd            = &cobra.Command{
 		Use:     "jitlab",
 		Short:   "Jitlab integrates Jira and GitLab for a faster development workflow",
 		Long:    ``,
@@ -35,24 +50,70 @@ var (
 )
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		initConfig(ctx)
+	}
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Config file (default is $HOME/.jitlab.json)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Profile of the config file to use (default is the one recorded by \"jitlab init\", if any)")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Give up after this long, e.g. 30s or 2m (default is no timeout)")
 
 	rootCmd.AddCommand(Config())
 	rootCmd.AddCommand(InitRepo())
 	rootCmd.AddCommand(NewTicket())
+	rootCmd.AddCommand(StartIssue())
 	rootCmd.AddCommand(Commits())
 	rootCmd.AddCommand(MergeRequest())
+	rootCmd.AddCommand(Auth())
+	rootCmd.AddCommand(Setup())
+	rootCmd.AddCommand(Doctor())
+}
+
+func initConfig(ctx context.Context) {
+	setupViper()
+
+	if err := viper.ReadInConfig(); err == nil {
+		log.Println("Using config file:", viper.ConfigFileUsed())
+	} else if os.IsNotExist(err) && hasEnvConfig() {
+		log.Printf("Config file %s not found, using environment variables only", viper.ConfigFileUsed())
+	} else if os.IsNotExist(err) {
+		log.Fatalf("Config file %s not found: run \"jitlab setup\" to create it, or set JITLAB_JIRA_BASEURL and the URL of your code host (e.g. JITLAB_GITLAB_BASEURL)", viper.ConfigFileUsed())
+	} else {
+		log.Fatalf("Error reading config file %s: %v", viper.ConfigFileUsed(), err)
+	}
+
+	// The profile and the repository settings are found through git.
+	initGitService()
+
+	if err := applyProfile(ctx); err != nil {
+		log.Fatalln(err)
+	}
+	if profileName != "" {
+		log.Println("Using profile:", profileName)
+	}
+
+	if err := applyRepositoryConfig(ctx); err != nil {
+		log.Printf("Ignoring the settings of the repository: %v", err)
+	}
+
+	if err := initServices(); err != nil {
+		log.Fatalln(err)
+	}
 }
 
-func initConfig() {
+func setupViper() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
@@ -63,37 +124,268 @@ func initConfig() {
 		viper.SetConfigFile(path.Join(home, ".jitlab.json"))
 	}
 
-	if err := viper.ReadInConfig(); err == nil {
-		log.Println("Using config file:", viper.ConfigFileUsed())
-	} else {
-		log.Fatalf("Config file %s not found", cfgFile)
+	// e.g. JITLAB_GITLAB_TOKEN for "gitlab.token", winning over the config file.
+	viper.SetEnvPrefix("jitlab")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
+
+	for key, value := range configDefaults {
+		viper.SetDefault(key, value)
 	}
+}
 
-	jiraUrl := viper.GetString("jira.baseurl")
-	validatedJiraBaseUrl, err := url.Parse(jiraUrl)
+var configDefaults = map[string]interface{}{
+	"maxIssues":        200,
+	"host":             host.Gitlab,
+	"github.baseurl":   "https://api.github.com",
+	"http.timeout":     "30s",
+	"http.retries":     3,
+	"http.backoff":     "500ms",
+	"http.maxWait":     "1m",
+	"credentials.file": "~/.jitlab.credentials",
+}
+
+// Services needing a token are built on first use, as reading it may take a
+// decryption or a keyring prompt. Only an invalid credential store is reported.
+func initServices() error {
+	commandClient := command.CommandClientImpl{}
+
+	retryClient := rest.RetryClient{
+		Client:     &http.Client{Timeout: viper.GetDuration("http.timeout")},
+		MaxRetries: viper.GetInt("http.retries"),
+		Backoff:    viper.GetDuration("http.backoff"),
+		MaxWait:    viper.GetDuration("http.maxWait")}
+	restClient = rest.RestClientImpl{Client: retryClient}
+	cachedJiraService = nil
+	cachedHostServices = map[string]host.HostService{}
+	initGitService()
+	questionService = question.QuestionServiceImpl{}
+
+	credentialStore = nil
+	credentialFile, err := homedir.Expand(viper.GetString("credentials.file"))
 	if err != nil {
-		log.Fatalf("Jira base URL %s is not a valid", jiraUrl)
+		return err
 	}
-	gitlabUrl := viper.GetString("gitlab.baseurl")
-	validatedGitlabBaseUrl, err := url.Parse(gitlabUrl)
+	store, err := credential.NewStore(commandClient, credential.StoreOptions{
+		Type:      viper.GetString("credentials.store"),
+		File:      credentialFile,
+		Recipient: viper.GetString("credentials.recipient"),
+		Identity:  viper.GetString("credentials.identity")})
 	if err != nil {
-		log.Fatalf("Gitlab base URL %s is not a valid", gitlabUrl)
+		return err
 	}
-	jiraToken := viper.GetString("jira.token")
-	jiraUsername := viper.GetString("jira.username")
+	credentialStore = store
 
-	gitlabToken := viper.GetString("gitlab.token")
-	gitlabGroup := viper.GetString("gitlab.groupid")
+	return nil
+}
 
+func initGitService() {
 	branchPrefix := viper.GetString("branchPrefix")
 	branchSuffix := viper.GetString("branchSuffix")
 	keyCommitSeparator := viper.GetString("keyCommitSeparator")
 	branchRegex := regexp.MustCompile(fmt.Sprintf("(%s)(\\w{1,6}-\\d{1,5})-(.*)(%s)", branchPrefix, branchSuffix))
 
-	client := rest.RestClientImpl{Client: http.DefaultClient}
-	commandClient := command.CommandClientImpl{}
-	jiraService = jira.JiraServiceImpl{Client: client, BaseURL: validatedJiraBaseUrl.String(), Token: jiraToken, Username: jiraUsername}
-	gitlabService = gitlab.GitlabServiceImpl{Client: client, BaseURL: validatedGitlabBaseUrl.String(), Token: gitlabToken, Group: gitlabGroup}
-	gitService = git.GitServiceImpl{CommandClient: commandClient, BranchPrefix: branchPrefix, BranchSuffix: branchSuffix, KeyCommitSeparator: keyCommitSeparator, BranchRegexp: branchRegex}
-	questionService = question.QuestionServiceImpl{}
+	gitService = git.GitServiceImpl{CommandClient: command.CommandClientImpl{}, BranchPrefix: branchPrefix, BranchSuffix: branchSuffix, KeyCommitSeparator: keyCommitSeparator, BranchRegexp: branchRegex}
+}
+
+func getJiraService(ctx context.Context) (jira.JiraService, error) {
+	if cachedJiraService == nil {
+		service, err := newJiraService(lookupSecret(ctx, "jira.token"))
+		if err != nil {
+			return nil, err
+		}
+		cachedJiraService = service
+	}
+
+	return cachedJiraService, nil
+}
+
+func newJiraService(token string) (jira.JiraService, error) {
+	jiraUrl := viper.GetString("jira.baseurl")
+	validatedJiraBaseUrl, err := url.Parse(jiraUrl)
+	if err != nil {
+		return nil, fmt.Errorf("Jira base URL %s is not valid", jiraUrl)
+	}
+
+	jiraAuth, err := jira.NewAuth(viper.GetString("jira.auth"), viper.GetString("jira.username"), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return jira.JiraServiceImpl{Client: restClient, BaseURL: validatedJiraBaseUrl.String(), Auth: jiraAuth, ApiVersion: viper.GetString("jira.apiVersion")}, nil
+}
+
+func hostService(ctx context.Context, name string) (host.HostService, error) {
+	if service, found := cachedHostServices[name]; found {
+		return service, nil
+	}
+
+	if !isHost(name) {
+		return nil, fmt.Errorf("unknown code host \"%s\"", name)
+	}
+
+	service, err := newHostService(name, lookupSecret(ctx, name+".token"))
+	if err != nil {
+		return nil, err
+	}
+	cachedHostServices[name] = service
+
+	return service, nil
+}
+
+func newHostService(name string, token string) (host.HostService, error) {
+	baseUrl := viper.GetString(name + ".baseurl")
+	validatedBaseUrl, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%s base URL %s is not valid", name, baseUrl)
+	}
+
+	switch name {
+	case host.Gitlab:
+		return gitlab.GitlabServiceImpl{Client: restClient, BaseURL: validatedBaseUrl.String(), Token: token, Group: viper.GetString("gitlab.groupid")}, nil
+	case host.Github:
+		return github.GithubServiceImpl{Client: restClient, BaseURL: validatedBaseUrl.String(), Token: token, Owner: viper.GetString("github.owner")}, nil
+	case host.Bitbucket:
+		return bitbucket.BitbucketServiceImpl{Client: restClient, BaseURL: validatedBaseUrl.String(), Token: token, Project: viper.GetString("bitbucket.project")}, nil
+	default:
+		return nil, fmt.Errorf("unknown code host \"%s\"", name)
+	}
+}
+
+func isHost(name string) bool {
+	return name == host.Gitlab || name == host.Github || name == host.Bitbucket
+}
+
+// lookupSecret looks at the environment, the credential store and the config
+// file, in this order.
+func lookupSecret(ctx context.Context, key string) string {
+	secret, _ := findSecret(ctx, key)
+
+	return secret
+}
+
+func findSecret(ctx context.Context, key string) (string, string) {
+	if secret, found := credential.FromEnv(key); found {
+		return secret, fmt.Sprintf("environment (%s)", credential.EnvName(key))
+	}
+
+	if credentialStore != nil {
+		// Like the other settings, a profile inherits the top level token.
+		storeKeys := []string{profileKey(key)}
+		if profileName != "" {
+			storeKeys = append(storeKeys, key)
+		}
+
+		for _, storeKey := range storeKeys {
+			secret, err := credentialStore.Get(ctx, storeKey)
+			if err == nil {
+				return secret, fmt.Sprintf("%s store (%s)", viper.GetString("credentials.store"), storeKey)
+			}
+			if !errors.Is(err, credential.ErrNotFound) {
+				// Falling back to a plaintext token would hide the problem.
+				log.Printf("Could not read %s from the credential store: %v", storeKey, err)
+				return "", ""
+			}
+		}
+	}
+
+	if secret := viper.GetString(key); secret != "" {
+		return secret, fmt.Sprintf("config file %s (plaintext)", viper.ConfigFileUsed())
+	}
+
+	return "", ""
+}
+
+// Unlike viper.WriteConfigAs, writeConfig doesn't write defaults or merged
+// settings. A nil value removes the key.
+func writeConfig(file string, values map[string]interface{}) error {
+	config := map[string]interface{}{}
+
+	content, err := ioutil.ReadFile(file)
+	if err == nil {
+		if err := json.Unmarshal(content, &config); err != nil {
+			return fmt.Errorf("config file %s is not valid: %v", file, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for key, value := range values {
+		setNested(config, strings.Split(key, "."), value)
+	}
+
+	content, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(file, content, 0600); err != nil {
+		return err
+	}
+
+	// WriteFile keeps the mode of an existing file.
+	return os.Chmod(file, 0600)
+}
+
+// Like viper, setNested matches existing keys regardless of case.
+func setNested(config map[string]interface{}, path []string, value interface{}) {
+	key := path[0]
+	for existing := range config {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+
+	if len(path) == 1 && value == nil {
+		delete(config, key)
+		return
+	}
+	if len(path) == 1 {
+		config[key] = value
+		return
+	}
+
+	child, ok := config[key].(map[string]interface{})
+	if !ok && value == nil {
+		return
+	}
+	if !ok {
+		child = map[string]interface{}{}
+		config[key] = child
+	}
+
+	setNested(child, path[1:], value)
+}
+
+// Lists in the environment are comma separated, as items may contain spaces.
+func configStringSlice(key string) []string {
+	value, found := os.LookupEnv(credential.EnvName(key))
+	if !found {
+		return viper.GetStringSlice(key)
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// hasEnvConfig tells whether, without a config file, the environment sets
+// the URLs of Jira and of the code host.
+func hasEnvConfig() bool {
+	return viper.GetString("jira.baseurl") != "" && viper.GetString(viper.GetString("host")+".baseurl") != ""
+}
+
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return context.WithCancel(cmd.Context())
+	}
+
+	return context.WithTimeout(cmd.Context(), timeout)
 }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/credential"
+	"github.com/spf13/viper"
+)
+
+type fakeStore map[string]string
+
+func (s fakeStore) Get(ctx context.Context, key string) (string, error) {
+	secret, found := s[key]
+	if !found {
+		return "", credential.ErrNotFound
+	}
+
+	return secret, nil
+}
+
+func (s fakeStore) Set(ctx context.Context, key string, secret string) error {
+	s[key] = secret
+	return nil
+}
+
+func (s fakeStore) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func TestFindSecret(t *testing.T) {
+	tests := map[string]struct {
+		profile string
+		env     string
+		store   fakeStore
+		config  string
+		want    string
+	}{
+		"Top level token in the store":           {"", "", fakeStore{"gitlab.token": "base"}, "", "base"},
+		"Profile token in the store":             {"client", "", fakeStore{"profiles.client.gitlab.token": "client", "gitlab.token": "base"}, "", "client"},
+		"Profile inheriting the top level token": {"client", "", fakeStore{"gitlab.token": "base"}, "plain", "base"},
+		"Profile token of another profile":       {"client", "", fakeStore{"profiles.other.gitlab.token": "other"}, "", ""},
+		"Environment wins over the store":        {"client", "env", fakeStore{"profiles.client.gitlab.token": "client"}, "", "env"},
+		"Config file when the store misses":      {"client", "", fakeStore{}, "plain", "plain"},
+		"Config file without a store":            {"", "", nil, "plain", "plain"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			defer func() { profileName, credentialStore = "", nil }()
+
+			profileName = tc.profile
+			credentialStore = nil
+			if tc.store != nil {
+				credentialStore = tc.store
+			}
+			if tc.config != "" {
+				viper.Set("gitlab.token", tc.config)
+			}
+			if tc.env != "" {
+				os.Setenv("JITLAB_GITLAB_TOKEN", tc.env)
+				defer os.Unsetenv("JITLAB_GITLAB_TOKEN")
+			}
+
+			result, _ := findSecret(context.Background(), "gitlab.token")
+
+			if result != tc.want {
+				t.Errorf("Wanted '%s', got '%s'", tc.want, result)
+			}
+		})
+	}
+}
+
+func TestHasEnvConfig(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want bool
+	}{
+		"Nothing":                   {map[string]string{}, false},
+		"Only a timeout":            {map[string]string{"JITLAB_HTTP_TIMEOUT": "10s"}, false},
+		"Jira only":                 {map[string]string{"JITLAB_JIRA_BASEURL": "https://jira.example.com"}, false},
+		"Jira and GitLab":           {map[string]string{"JITLAB_JIRA_BASEURL": "https://jira.example.com", "JITLAB_GITLAB_BASEURL": "https://gitlab.example.com/api/v4"}, true},
+		"Jira and default GitHub":   {map[string]string{"JITLAB_JIRA_BASEURL": "https://jira.example.com", "JITLAB_HOST": "github"}, true},
+		"Bitbucket without its URL": {map[string]string{"JITLAB_JIRA_BASEURL": "https://jira.example.com", "JITLAB_HOST": "bitbucket", "JITLAB_GITLAB_BASEURL": "https://gitlab.example.com/api/v4"}, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			setupViper()
+
+			for key, value := range tc.env {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+
+			if result := hasEnvConfig(); result != tc.want {
+				t.Errorf("Wanted %t, got %t", tc.want, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/credential"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/jira"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type setupQuestion struct {
+	key          string
+	message      string
+	defaultValue string
+	secret       bool
+	validator    func(string) error
+	// skip tells, from the previous answers, whether the question applies.
+	skip func() bool
+}
+
+func Setup() *cobra.Command {
+	setupCmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Create your configuration file",
+		Long:  `Run this command the first time you use Jitlab: it asks for your GitLab and Jira details, checks them and writes the configuration file`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			setupViper()
+
+			if err := viper.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+				log.Fatalf("Error reading config file %s: %v", viper.ConfigFileUsed(), err)
+			}
+
+			initGitService()
+
+			// A profile that doesn't exist yet is created by this command.
+			if err := applyProfile(ctx); err != nil {
+				log.Printf("Creating profile %s", profileName)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			log.Printf("Setting up %s...", viper.ConfigFileUsed())
+
+			questions := []setupQuestion{
+				{key: "gitlab.baseurl", message: "GitLab API URL:", defaultValue: "https://gitlab.com/api/v4", validator: validateBaseUrl},
+				{key: "gitlab.token", message: "GitLab token (with api scope):", secret: true},
+				{key: "gitlab.groupid", message: "GitLab group ID:", validator: required},
+				{key: "jira.baseurl", message: "Jira URL:", validator: validateBaseUrl},
+				{key: "jira.auth", message: "Jira authentication (basic for Jira Cloud, bearer for personal access tokens):", defaultValue: jira.BasicAuthType, validator: validateJiraAuth},
+				{key: "jira.apiVersion", message: "Jira API version (3 for Jira Cloud, 2 for Jira Server and Data Center):", defaultValue: "3", validator: validateJiraApiVersion},
+				{key: "jira.username", message: "Jira username (your email on Jira Cloud):", validator: required, skip: isBearerAuth},
+				{key: "jira.token", message: "Jira API or personal access token:", secret: true},
+				{key: "branchPrefix", message: "Branch prefix:"},
+				{key: "branchSuffix", message: "Branch suffix:"},
+				{key: "keyCommitSeparator", message: "Separator between issue key and commit message:", defaultValue: ":"},
+			}
+
+			answers := map[string]interface{}{}
+			secrets := map[string]string{}
+			for _, q := range questions {
+				if q.skip != nil && q.skip() {
+					continue
+				}
+				answer, err := askSetupQuestion(q)
+				if err != nil {
+					log.Fatalln(err)
+				}
+				if q.secret {
+					secrets[q.key] = answer
+					continue
+				}
+				answers[profileKey(q.key)] = answer
+				viper.Set(q.key, answer)
+			}
+
+			if err := initServices(); err != nil {
+				log.Fatalln(err)
+			}
+
+			if !checkSetup(ctx, secrets) {
+				save, err := questionService.AskForConfirmation("Some checks failed. Save the configuration anyway?", false)
+				if err != nil {
+					log.Fatalln(err)
+				}
+				if !save {
+					log.Fatalln("Configuration not saved")
+				}
+			}
+
+			for key, secret := range secrets {
+				if _, found := credential.FromEnv(key); found {
+					log.Printf("%s is set and still overrides the %s you entered", credential.EnvName(key), key)
+				}
+				if credentialStore == nil {
+					answers[profileKey(key)] = secret
+					continue
+				}
+				if err := credentialStore.Set(ctx, profileKey(key), secret); err != nil {
+					log.Fatalln(err)
+				}
+				log.Printf("%s saved in the %s store", key, viper.GetString("credentials.store"))
+			}
+
+			if err := writeConfig(viper.ConfigFileUsed(), answers); err != nil {
+				log.Fatalln(err)
+			}
+			log.Printf("Configuration saved in %s. Now run \"jitlab config\" to choose your board", viper.ConfigFileUsed())
+		},
+	}
+
+	return setupCmd
+}
+
+func askSetupQuestion(q setupQuestion) (string, error) {
+	if q.secret {
+		return questionService.AskForSecret(q.message)
+	}
+
+	defaultValue := viper.GetString(q.key)
+	if defaultValue == "" {
+		defaultValue = q.defaultValue
+	}
+
+	return questionService.AskForText(q.message, defaultValue, q.validator)
+}
+
+// A token in the environment or in the credential store would take precedence
+// over the typed one, so the services are built from the answers.
+func checkSetup(ctx context.Context, secrets map[string]string) bool {
+	success := true
+
+	gitlabService, err := newHostService(host.Gitlab, secrets["gitlab.token"])
+	var gitlabUser string
+	if err == nil {
+		gitlabUser, err = gitlabService.CheckAuth(ctx)
+	}
+	if err != nil {
+		log.Printf("GitLab check failed: %v", explain(err))
+		success = false
+	} else {
+		log.Printf("GitLab: authenticated as %s", gitlabUser)
+	}
+
+	var jiraUser jira.User
+	jiraService, err := newJiraService(secrets["jira.token"])
+	if err == nil {
+		jiraUser, err = jiraService.GetCurrentUser(ctx)
+	}
+	if err != nil {
+		log.Printf("Jira check failed: %v", explain(err))
+		success = false
+	} else {
+		log.Printf("Jira: authenticated as %s", jiraUser.DisplayName)
+	}
+
+	return success
+}
+
+func validateBaseUrl(value string) error {
+	parsedUrl, err := url.ParseRequestURI(value)
+	if err != nil || parsedUrl.Host == "" || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") {
+		return errors.New("please enter a full URL, such as https://example.com")
+	}
+
+	return nil
+}
+
+func validateJiraAuth(value string) error {
+	_, err := jira.NewAuth(value, "", "")
+
+	return err
+}
+
+func validateJiraApiVersion(value string) error {
+	if value != "2" && value != "3" {
+		return errors.New("please enter 2 or 3")
+	}
+
+	return nil
+}
+
+func isBearerAuth() bool {
+	return strings.EqualFold(viper.GetString("jira.auth"), jira.BearerAuthType)
+}
+
+func required(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("this value is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/rest"
+	"github.com/spf13/cobra"
+)
+
+func StartIssue() *cobra.Command {
+	startCmd := &cobra.Command{
+		Use:   "start <issue-key>",
+		Short: "Start working on a known issue",
+		Long:  `Run this command to create a branch for a jira issue when you already know its key`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			issueKey := strings.ToUpper(strings.TrimSpace(args[0]))
+			log.Printf("Starting issue %s...", issueKey)
+
+			jiraService, err := getJiraService(ctx)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			issue, err := jiraService.GetIssue(ctx, issueKey)
+			if err != nil {
+				var apiError *rest.ApiError
+				if errors.As(err, &apiError) && apiError.IsNotFound() {
+					log.Fatalf("Issue \"%s\" doesn't exist or you don't have permission to see it", issueKey)
+				}
+				log.Fatalf("Could not read issue \"%s\": %v", issueKey, explain(err))
+			}
+
+			newBranch, err := gitService.CreateBranch(ctx, issue)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			log.Printf("New branch \"%s\" created", newBranch)
+
+			transitionIssue(ctx, issue.Key, branchCreatedEvent)
+		},
+	}
+
+	return startCmd
+
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/boh717/jitlab/pkg/jira"
+	"github.com/spf13/viper"
+)
+
+const (
+	branchCreatedEvent = "branchCreated"
+	mrOpenedEvent      = "mrOpened"
+)
+
+// Failures are only logged: the git side of the work is already done.
+func transitionIssue(ctx context.Context, issueKey string, event string) {
+	status := viper.GetString("transitions." + event)
+	if issueKey == "" || status == "" {
+		return
+	}
+
+	jiraService, err := getJiraService(ctx)
+	if err != nil {
+		log.Printf("Could not move issue %s: %v", issueKey, err)
+		return
+	}
+
+	transitions, err := jiraService.GetTransitions(ctx, issueKey)
+	if err != nil {
+		log.Printf("Could not read transitions of issue %s: %v", issueKey, explain(err))
+		return
+	}
+
+	transition, found := jira.FindTransition(transitions, status)
+	if !found {
+		log.Printf("Issue %s can't be moved to \"%s\" from its current status", issueKey, status)
+		return
+	}
+
+	if err := jiraService.DoTransition(ctx, issueKey, transition); err != nil {
+		log.Printf("Could not move issue %s to \"%s\": %v", issueKey, status, explain(err))
+		return
+	}
+
+	log.Printf("Issue %s moved to \"%s\"", issueKey, status)
+}
@@ -1,13 +1,35 @@
 package command
 
-import "os/exec"
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
 
 type CommandClient interface {
-	Run(command string, args ...string) ([]byte, error)
+	Run(ctx context.Context, command string, args ...string) ([]byte, error)
+	RunWithInput(ctx context.Context, input string, command string, args ...string) ([]byte, error)
 }
 
 type CommandClientImpl struct{}
 
-func (c CommandClientImpl) Run(command string, args ...string) ([]byte, error) {
-	return exec.Command(command, args...).CombinedOutput()
+func (c CommandClientImpl) Run(ctx context.Context, command string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, command, args...).CombinedOutput()
+}
+
+// Secrets fed through stdin stay out of the process arguments. Only stdout
+// is returned, so that warnings can't end up in the output.
+func (c CommandClientImpl) RunWithInput(ctx context.Context, input string, command string, args ...string) ([]byte, error) {
+	var stderr strings.Builder
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return []byte(stderr.String()), err
+	}
+
+	return out, nil
 }
@@ -0,0 +1,70 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/command"
+)
+
+const (
+	ConfigStoreType  = "config"
+	KeyringStoreType = "keyring"
+	GpgStoreType     = "gpg"
+	AgeStoreType     = "age"
+)
+
+var ErrNotFound = errors.New("credential not found")
+
+// Store keeps tokens out of the config file. Keys are config keys.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, secret string) error
+	Delete(ctx context.Context, key string) error
+}
+
+type StoreOptions struct {
+	Type      string
+	File      string
+	Recipient string
+	Identity  string
+}
+
+// With the "config" type there is no store: tokens stay in the config file.
+func NewStore(commandClient command.CommandClient, options StoreOptions) (Store, error) {
+	switch strings.ToLower(options.Type) {
+	case "", ConfigStoreType:
+		return nil, nil
+	case KeyringStoreType:
+		return KeyringStore{CommandClient: commandClient}, nil
+	case GpgStoreType, AgeStoreType:
+		if options.File == "" || options.Recipient == "" {
+			return nil, fmt.Errorf("the %s store needs both a file and a recipient", options.Type)
+		}
+		return EncryptedFileStore{
+			CommandClient: commandClient,
+			Tool:          strings.ToLower(options.Type),
+			Path:          options.File,
+			Recipient:     options.Recipient,
+			Identity:      options.Identity}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store \"%s\"", options.Type)
+	}
+}
+
+// EnvName returns the environment variable overriding key, e.g.
+// JITLAB_JIRA_TOKEN for "jira.token".
+func EnvName(key string) string {
+	replacer := strings.NewReplacer(".", "_", "-", "_")
+
+	return "JITLAB_" + strings.ToUpper(replacer.Replace(key))
+}
+
+func FromEnv(key string) (string, bool) {
+	secret, found := os.LookupEnv(EnvName(key))
+
+	return secret, found && secret != ""
+}
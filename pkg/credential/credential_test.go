@@ -0,0 +1,156 @@
+package credential_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/credential"
+	"github.com/boh717/jitlab/pkg/mocks"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]struct {
+		key      string
+		expected string
+	}{
+		"Jira token":    {"jira.token", "JITLAB_JIRA_TOKEN"},
+		"Gitlab token":  {"gitlab.token", "JITLAB_GITLAB_TOKEN"},
+		"Top level key": {"branchPrefix", "JITLAB_BRANCHPREFIX"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := credential.EnvName(tc.key)
+
+			if result != tc.expected {
+				t.Errorf("Wanted '%s', got '%s'", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	os.Setenv("JITLAB_JIRA_TOKEN", "secret")
+	defer os.Unsetenv("JITLAB_JIRA_TOKEN")
+
+	secret, found := credential.FromEnv("jira.token")
+	if !found || secret != "secret" {
+		t.Errorf("Got '%s' (found %t), wanted 'secret'", secret, found)
+	}
+
+	if _, found := credential.FromEnv("gitlab.token"); found {
+		t.Errorf("Found a gitlab token, but none was set")
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	tests := map[string]struct {
+		options       credential.StoreOptions
+		expectedNil   bool
+		expectedError bool
+	}{
+		"Config file":          {credential.StoreOptions{}, true, false},
+		"Keyring":              {credential.StoreOptions{Type: "keyring"}, false, false},
+		"Gpg file":             {credential.StoreOptions{Type: "gpg", File: "secrets.gpg", Recipient: "me@example.com"}, false, false},
+		"Age file without key": {credential.StoreOptions{Type: "age", File: "secrets.age"}, true, true},
+		"Unknown store":        {credential.StoreOptions{Type: "vault"}, true, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := credential.NewStore(mocks.MockCommandClient{}, tc.options)
+
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Wanted error to be %t, got '%v'", tc.expectedError, err)
+			}
+
+			if tc.expectedNil != (store == nil) {
+				t.Errorf("Wanted nil store to be %t, got '%+v'", tc.expectedNil, store)
+			}
+		})
+	}
+}
+
+func TestKeyringStoreGet(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("the Keychain reports missing items with another exit status")
+	}
+	notFoundErr := exec.Command("sh", "-c", "exit 1").Run()
+	tests := map[string]struct {
+		out              string
+		err              error
+		expectedSecret   string
+		expectedNotFound bool
+	}{
+		"Stored secret":  {"secret\n", nil, "secret", false},
+		"Missing secret": {"", notFoundErr, "", true},
+		"Locked keyring": {"secret-tool: Cannot get secret of a locked object", notFoundErr, "", false},
+	}
+	store := credential.KeyringStore{CommandClient: mocks.MockCommandClient{}}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.RunFakeCommandWithInput = func(input string, command string, args ...string) ([]byte, error) {
+				return []byte(tc.out), tc.err
+			}
+
+			secret, err := store.Get(context.Background(), "jira.token")
+
+			if errors.Is(err, credential.ErrNotFound) != tc.expectedNotFound {
+				t.Errorf("Got error '%v', wanted not found %t", err, tc.expectedNotFound)
+			}
+
+			if tc.expectedSecret == "" && err == nil {
+				t.Errorf("Got secret '%s', but wanted an error", secret)
+			}
+
+			if secret != tc.expectedSecret {
+				t.Errorf("Wanted secret '%s'. Got secret '%s' instead", tc.expectedSecret, secret)
+			}
+		})
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	file := path.Join(t.TempDir(), "secrets.age")
+	var encrypted string
+	mocks.RunFakeCommandWithInput = func(input string, command string, args ...string) ([]byte, error) {
+		if args[0] == "--encrypt" {
+			encrypted = input
+			return nil, os.WriteFile(file, []byte("encrypted"), 0600)
+		}
+		return []byte(encrypted), nil
+	}
+	store := credential.EncryptedFileStore{CommandClient: mocks.MockCommandClient{}, Tool: "age", Path: file, Recipient: "age1recipient", Identity: "key.txt"}
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "jira.token"); !errors.Is(err, credential.ErrNotFound) {
+		t.Fatalf("Got '%v' from an empty store, wanted ErrNotFound", err)
+	}
+
+	if err := store.Set(ctx, "jira.token", "secret"); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if encrypted != `{"jira.token":"secret"}` {
+		t.Errorf("Got unexpected plaintext '%s'", encrypted)
+	}
+
+	secret, err := store.Get(ctx, "jira.token")
+	if err != nil || secret != "secret" {
+		t.Errorf("Got '%s' and error '%v', wanted 'secret'", secret, err)
+	}
+
+	if err := store.Delete(ctx, "jira.token"); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if _, err := store.Get(ctx, "jira.token"); !errors.Is(err, credential.ErrNotFound) {
+		t.Errorf("Got '%v' after delete, wanted ErrNotFound", err)
+	}
+}
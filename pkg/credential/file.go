@@ -0,0 +1,103 @@
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/boh717/jitlab/pkg/command"
+)
+
+// Identity is the age identity file. gpg relies on its agent instead.
+type EncryptedFileStore struct {
+	CommandClient command.CommandClient
+	Tool          string
+	Path          string
+	Recipient     string
+	Identity      string
+}
+
+func (e EncryptedFileStore) Get(ctx context.Context, key string) (string, error) {
+	secrets, err := e.read(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	secret, found := secrets[key]
+	if !found || secret == "" {
+		return "", ErrNotFound
+	}
+
+	return secret, nil
+}
+
+func (e EncryptedFileStore) Set(ctx context.Context, key string, secret string) error {
+	secrets, err := e.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	secrets[key] = secret
+
+	return e.write(ctx, secrets)
+}
+
+func (e EncryptedFileStore) Delete(ctx context.Context, key string) error {
+	secrets, err := e.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	delete(secrets, key)
+
+	return e.write(ctx, secrets)
+}
+
+func (e EncryptedFileStore) read(ctx context.Context) (map[string]string, error) {
+	secrets := map[string]string{}
+
+	if _, err := os.Stat(e.Path); errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+
+	var args []string
+	if e.Tool == AgeStoreType {
+		args = []string{"--decrypt", "--identity", e.Identity, e.Path}
+	} else {
+		args = []string{"--decrypt", "--quiet", "--batch", e.Path}
+	}
+
+	out, err := e.CommandClient.RunWithInput(ctx, "", e.Tool, args...)
+	if err != nil {
+		return nil, errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	if err := json.Unmarshal(out, &secrets); err != nil {
+		return nil, fmt.Errorf("credential file %s is not valid: %v", e.Path, err)
+	}
+
+	return secrets, nil
+}
+
+func (e EncryptedFileStore) write(ctx context.Context, secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if e.Tool == AgeStoreType {
+		args = []string{"--encrypt", "--recipient", e.Recipient, "--output", e.Path}
+	} else {
+		args = []string{"--encrypt", "--batch", "--yes", "--recipient", e.Recipient, "--output", e.Path}
+	}
+
+	out, err := e.CommandClient.RunWithInput(ctx, string(plaintext), e.Tool, args...)
+	if err != nil {
+		return errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	return nil
+}
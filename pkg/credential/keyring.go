@@ -0,0 +1,108 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/command"
+)
+
+const keyringService = "jitlab"
+
+// securityItemNotFound is the exit status of security for a missing item.
+const securityItemNotFound = 44
+
+// KeyringStore uses the OS keyring: the Secret Service (through secret-tool)
+// on Linux and the Keychain (through security) on macOS.
+type KeyringStore struct {
+	CommandClient command.CommandClient
+}
+
+func (k KeyringStore) Get(ctx context.Context, key string) (string, error) {
+	var out []byte
+	var err error
+
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = k.CommandClient.RunWithInput(ctx, "", "security", "find-generic-password", "-s", keyringService, "-a", key, "-w")
+	default:
+		out, err = k.CommandClient.RunWithInput(ctx, "", "secret-tool", "lookup", "service", keyringService, "account", key)
+	}
+
+	if err != nil {
+		if isKeyringItemNotFound(err, out) {
+			return "", ErrNotFound
+		}
+		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	secret := strings.TrimRight(string(out), "\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+
+	return secret, nil
+}
+
+func (k KeyringStore) Set(ctx context.Context, key string, secret string) error {
+	var out []byte
+	var err error
+
+	switch runtime.GOOS {
+	case "darwin":
+		// In interactive mode security reads the command from stdin, so the
+		// secret never shows up in the process arguments.
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", securityQuote(keyringService), securityQuote(key), securityQuote(secret))
+		out, err = k.CommandClient.RunWithInput(ctx, command, "security", "-i")
+	default:
+		out, err = k.CommandClient.RunWithInput(ctx, secret, "secret-tool", "store", "--label", fmt.Sprintf("jitlab %s", key), "service", keyringService, "account", key)
+	}
+
+	if err != nil {
+		return errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	return nil
+}
+
+func (k KeyringStore) Delete(ctx context.Context, key string) error {
+	var out []byte
+	var err error
+
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = k.CommandClient.Run(ctx, "security", "delete-generic-password", "-s", keyringService, "-a", key)
+	default:
+		out, err = k.CommandClient.Run(ctx, "secret-tool", "clear", "service", keyringService, "account", key)
+	}
+
+	if err != nil {
+		return errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	return nil
+}
+
+// A locked keyring or a denied prompt isn't a missing item. secret-tool exits
+// with 1 and prints nothing when there's no item.
+func isKeyringItemNotFound(err error, stderr []byte) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+
+	if runtime.GOOS == "darwin" {
+		return exitErr.ExitCode() == securityItemNotFound
+	}
+
+	return exitErr.ExitCode() == 1 && strings.TrimSpace(string(stderr)) == ""
+}
+
+// securityQuote quotes an argument for the interactive mode of security.
+func securityQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
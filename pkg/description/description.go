@@ -0,0 +1,44 @@
+package description
+
+import (
+	"strings"
+	"text/template"
+)
+
+// DefaultTemplate is used when no "mrTemplate" is set in the config file.
+const DefaultTemplate = `{{ with .Issue }}{{ if .Key }}## [{{ .Key }}]({{ .Url }}) {{ .Summary }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}{{ end }}{{ end }}{{ if .Commits }}
+### Commits
+
+{{ range .Commits }}- {{ . }}
+{{ end }}{{ end }}`
+
+type Issue struct {
+	Key         string
+	Summary     string
+	Description string
+	Url         string
+}
+
+type Data struct {
+	Branch       string
+	TargetBranch string
+	Issue        Issue
+	Commits      []string
+}
+
+func Render(text string, data Data) (string, error) {
+	tmpl, err := template.New("description").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var description strings.Builder
+	if err := tmpl.Execute(&description, data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(description.String()), nil
+}
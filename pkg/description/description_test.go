@@ -0,0 +1,41 @@
+package description_test
+
+import (
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/description"
+)
+
+func TestRender(t *testing.T) {
+	issue := description.Issue{Key: "JT-01", Summary: "Complete this task", Description: "Some details", Url: "https://jira.example.com/browse/JT-01"}
+	tests := map[string]struct {
+		template      string
+		data          description.Data
+		expected      string
+		expectedError bool
+	}{
+		"Default template with issue and commits": {description.DefaultTemplate, description.Data{Issue: issue, Commits: []string{"JT-01: Add feature X", "JT-01: Fix tests"}},
+			"## [JT-01](https://jira.example.com/browse/JT-01) Complete this task\n\nSome details\n\n### Commits\n\n- JT-01: Add feature X\n- JT-01: Fix tests", false},
+		"Default template without issue": {description.DefaultTemplate, description.Data{Commits: []string{"Add feature X"}},
+			"### Commits\n\n- Add feature X", false},
+		"Default template without commits": {description.DefaultTemplate, description.Data{Issue: description.Issue{Key: "JT-01", Summary: "Complete this task", Url: "https://jira.example.com/browse/JT-01"}},
+			"## [JT-01](https://jira.example.com/browse/JT-01) Complete this task", false},
+		"Custom template":  {"Closes {{ .Issue.Key }} ({{ .Branch }} -> {{ .TargetBranch }})", description.Data{Branch: "feature", TargetBranch: "master", Issue: issue}, "Closes JT-01 (feature -> master)", false},
+		"Broken template":  {"{{ .Issue.Key ", description.Data{}, "", true},
+		"Unknown variable": {"{{ .Ticket }}", description.Data{}, "", true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := description.Render(tc.template, tc.data)
+
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Wanted error to be %t, got '%v'", tc.expectedError, err)
+			}
+
+			if result != tc.expected {
+				t.Errorf("Got description '%s', but wanted '%s'", result, tc.expected)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package git
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -11,11 +14,17 @@ import (
 )
 
 type GitService interface {
-	GetCurrentBranch() (string, error)
-	CreateBranch(issue jira.Issue) (string, error)
+	GetCurrentBranch(ctx context.Context) (string, error)
+	CreateBranch(ctx context.Context, issue jira.Issue) (string, error)
 	CreateTitleFromBranch(branch string) (string, error)
-	Commit(branch string, message string) (string, error)
-	Push(branch string) (string, error)
+	GetIssueKey(branch string) string
+	Commit(ctx context.Context, branch string, message string) (string, error)
+	Push(ctx context.Context, branch string) (string, error)
+	GetCommits(ctx context.Context, targetBranch string) ([]string, error)
+	GetRemoteUrl(ctx context.Context, remote string) (string, error)
+	GetRepositoryRoot(ctx context.Context) (string, error)
+	GetGitDir(ctx context.Context) (string, error)
+	IsTracked(ctx context.Context, path string) bool
 }
 
 type GitServiceImpl struct {
@@ -26,10 +35,10 @@ type GitServiceImpl struct {
 	BranchRegexp       *regexp.Regexp
 }
 
-func (g GitServiceImpl) GetCurrentBranch() (string, error) {
+func (g GitServiceImpl) GetCurrentBranch(ctx context.Context) (string, error) {
 	replacer := strings.NewReplacer(" ", "", "\n", "")
 
-	out, err := g.CommandClient.Run("git", "branch", "--show-current")
+	out, err := g.CommandClient.Run(ctx, "git", "branch", "--show-current")
 	if err != nil {
 		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
 	}
@@ -37,7 +46,7 @@ func (g GitServiceImpl) GetCurrentBranch() (string, error) {
 	return replacer.Replace(string(out)), nil
 }
 
-func (g GitServiceImpl) CreateBranch(issue jira.Issue) (string, error) {
+func (g GitServiceImpl) CreateBranch(ctx context.Context, issue jira.Issue) (string, error) {
 	replacer :=
 		strings.NewReplacer(" ", "-", "~", "", "^", "", ":", "", "?", "", "*", "", "[", "", "]", "", "{", "", "}", "", "\\", "")
 
@@ -46,7 +55,7 @@ func (g GitServiceImpl) CreateBranch(issue jira.Issue) (string, error) {
 
 	branchName := fmt.Sprintf("%s%s-%s%s", g.BranchPrefix, issueKey, summary, g.BranchSuffix)
 
-	out, err := g.CommandClient.Run("git", "switch", "-c", branchName)
+	out, err := g.CommandClient.Run(ctx, "git", "switch", "-c", branchName)
 	if err != nil {
 		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
 	}
@@ -74,7 +83,11 @@ func (g GitServiceImpl) CreateTitleFromBranch(branch string) (string, error) {
 
 }
 
-func (g GitServiceImpl) Commit(branch string, message string) (string, error) {
+func (g GitServiceImpl) GetIssueKey(branch string) string {
+	return getIssueKeyFromBranch(branch, g.BranchRegexp)
+}
+
+func (g GitServiceImpl) Commit(ctx context.Context, branch string, message string) (string, error) {
 	var commitMessage string
 
 	key := getIssueKeyFromBranch(branch, g.BranchRegexp)
@@ -84,7 +97,7 @@ func (g GitServiceImpl) Commit(branch string, message string) (string, error) {
 		commitMessage = fmt.Sprintf("%s%s %s", key, g.KeyCommitSeparator, commitMessage)
 	}
 
-	out, err := g.CommandClient.Run("git", "commit", "-m", commitMessage)
+	out, err := g.CommandClient.Run(ctx, "git", "commit", "-m", commitMessage)
 	if err != nil {
 		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
 	}
@@ -93,8 +106,8 @@ func (g GitServiceImpl) Commit(branch string, message string) (string, error) {
 
 }
 
-func (g GitServiceImpl) Push(branch string) (string, error) {
-	out, err := g.CommandClient.Run("git", "push", "--set-upstream", "origin", branch)
+func (g GitServiceImpl) Push(ctx context.Context, branch string) (string, error) {
+	out, err := g.CommandClient.Run(ctx, "git", "push", "--set-upstream", "origin", branch)
 	if err != nil {
 		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
 	}
@@ -103,6 +116,70 @@ func (g GitServiceImpl) Push(branch string) (string, error) {
 
 }
 
+func (g GitServiceImpl) GetCommits(ctx context.Context, targetBranch string) ([]string, error) {
+	out, err := g.CommandClient.Run(ctx, "git", "log", "--no-merges", "--format=%s", fmt.Sprintf("origin/%s..HEAD", targetBranch))
+	if err != nil {
+		return nil, errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	var commits []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if commit := strings.TrimSpace(line); commit != "" {
+			commits = append(commits, commit)
+		}
+	}
+
+	return commits, nil
+
+}
+
+func (g GitServiceImpl) GetRemoteUrl(ctx context.Context, remote string) (string, error) {
+	out, err := g.CommandClient.Run(ctx, "git", "remote", "get-url", remote)
+	if err != nil {
+		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+
+}
+
+func (g GitServiceImpl) GetRepositoryRoot(ctx context.Context) (string, error) {
+	out, err := g.CommandClient.Run(ctx, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// The common git directory is shared by all the worktrees.
+func (g GitServiceImpl) GetGitDir(ctx context.Context) (string, error) {
+	out, err := g.CommandClient.Run(ctx, "git", "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", errors.New(fmt.Sprint(err) + ": " + string(out))
+	}
+
+	return filepath.Abs(strings.TrimSpace(string(out)))
+}
+
+func (g GitServiceImpl) IsTracked(ctx context.Context, path string) bool {
+	_, err := g.CommandClient.Run(ctx, "git", "ls-files", "--error-unmatch", path)
+
+	return err == nil
+}
+
+// RemotePath returns "group/project" for both SSH and HTTPS remote URLs.
+func RemotePath(remoteUrl string) string {
+	remotePath := remoteUrl
+	if parsedUrl, err := url.Parse(remoteUrl); err == nil && parsedUrl.Host != "" {
+		remotePath = parsedUrl.Path
+	} else if i := strings.Index(remoteUrl, ":"); i >= 0 {
+		remotePath = remoteUrl[i+1:]
+	}
+
+	return strings.Trim(strings.TrimSuffix(strings.TrimRight(remotePath, "/"), ".git"), "/")
+}
+
 func getIssueKeyFromBranch(branch string, r *regexp.Regexp) string {
 	matches := r.FindStringSubmatch(branch)
 	if matches != nil {
@@ -1,14 +1,18 @@
 package git_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
 	"github.com/boh717/jitlab/pkg/git"
 	"github.com/boh717/jitlab/pkg/jira"
 	"github.com/boh717/jitlab/pkg/mocks"
+	"github.com/google/go-cmp/cmp"
 )
 
 var branchName = "your-branch-name"
@@ -30,7 +34,7 @@ func TestGetCurrentBranch(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			mocks.RunFakeCommand = tc.command
-			result, err := gitClient.GetCurrentBranch()
+			result, err := gitClient.GetCurrentBranch(context.Background())
 
 			if tc.expectedBranch == "" && err == nil {
 				t.Errorf("Got no branch nor error. Something unexpected happened!")
@@ -72,7 +76,7 @@ func TestCreateBranch(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			mocks.RunFakeCommand = tc.command
-			result, err := gitClient.CreateBranch(tc.issue)
+			result, err := gitClient.CreateBranch(context.Background(), tc.issue)
 
 			if tc.expectedBranch == "" && err == nil {
 				t.Errorf("Got no branch nor error. Something unexpected happened!")
@@ -120,6 +124,82 @@ func TestCreateTitleFromBranch(t *testing.T) {
 	}
 }
 
+func TestGetIssueKey(t *testing.T) {
+	tests := map[string]struct {
+		branch      string
+		expectedKey string
+	}{
+		"Return key from branch":       {"prefix/JT-01-complete-this-task", "JT-01"},
+		"Return nothing without a key": {"complete-this-task", ""},
+	}
+	gitClient := git.GitServiceImpl{
+		BranchPrefix: "prefix/",
+		BranchRegexp: regexp.MustCompile(fmt.Sprintf("(%s)(\\w{1,6}-\\d{1,5})-(.*)(%s)", "prefix/", "")),
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := gitClient.GetIssueKey(tc.branch)
+
+			if result != tc.expectedKey {
+				t.Errorf("Wanted key '%s'. Got key '%s' instead", tc.expectedKey, result)
+			}
+
+		})
+	}
+}
+
+func TestIsTracked(t *testing.T) {
+	tests := map[string]struct {
+		command         func(command string, args ...string) ([]byte, error)
+		expectedTracked bool
+	}{
+		"Tracked file": {func(command string, args ...string) ([]byte, error) {
+			return []byte(".repo\n"), nil
+		}, true},
+		"Untracked file": {func(command string, args ...string) ([]byte, error) {
+			return []byte("error: pathspec '.repo' did not match any file(s) known to git"), errors.New("exit status 1")
+		}, false},
+	}
+	mockCommandClient := mocks.MockCommandClient{}
+	gitClient := git.GitServiceImpl{CommandClient: mockCommandClient}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.RunFakeCommand = tc.command
+			result := gitClient.IsTracked(context.Background(), ".repo")
+
+			if result != tc.expectedTracked {
+				t.Errorf("Wanted tracked %t. Got %t instead", tc.expectedTracked, result)
+			}
+
+		})
+	}
+}
+
+func TestRemotePath(t *testing.T) {
+	tests := map[string]struct {
+		remoteUrl    string
+		expectedPath string
+	}{
+		"SSH scp-like url":       {"git@gitlab.com:group/subgroup/jitlab.git", "group/subgroup/jitlab"},
+		"SSH url":                {"ssh://git@bitbucket.example.com:7999/tools/jitlab.git", "tools/jitlab"},
+		"HTTPS url":              {"https://gitlab.com/group/jitlab.git", "group/jitlab"},
+		"HTTPS url without .git": {"https://github.com/boh717/jitlab/", "boh717/jitlab"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := git.RemotePath(tc.remoteUrl)
+
+			if result != tc.expectedPath {
+				t.Errorf("Wanted path '%s'. Got path '%s' instead", tc.expectedPath, result)
+			}
+
+		})
+	}
+}
+
 func TestCommit(t *testing.T) {
 	tests := map[string]struct {
 		command           func(command string, args ...string) ([]byte, error)
@@ -145,7 +225,7 @@ func TestCommit(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			mocks.RunFakeCommand = tc.command
-			result, err := gitClient.Commit(tc.branch, tc.message)
+			result, err := gitClient.Commit(context.Background(), tc.branch, tc.message)
 
 			if tc.expectedCommitMsg == "" && err == nil {
 				t.Errorf("Got no message nor error. Something unexpected happened!")
@@ -174,7 +254,7 @@ func TestPush(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			mocks.RunFakeCommand = tc.command
-			result, err := gitClient.Push(tc.branch)
+			result, err := gitClient.Push(context.Background(), tc.branch)
 
 			if tc.expectedPushMsg == "" && err == nil {
 				t.Errorf("Got no push message nor error. Something unexpected happened!")
@@ -188,6 +268,106 @@ func TestPush(t *testing.T) {
 	}
 }
 
+func TestGetCommits(t *testing.T) {
+	tests := map[string]struct {
+		command         func(command string, args ...string) ([]byte, error)
+		expectedCommits []string
+		expectedError   bool
+	}{
+		"Return commits": {func(command string, args ...string) ([]byte, error) {
+			return []byte("JT-01: Add feature X\nJT-01: Fix tests\n"), nil
+		}, []string{"JT-01: Add feature X", "JT-01: Fix tests"}, false},
+		"Return no commits": {func(command string, args ...string) ([]byte, error) { return []byte(""), nil }, nil, false},
+		"Return error":      {func(command string, args ...string) ([]byte, error) { return nil, errors.New("Fatal!") }, nil, true},
+	}
+	mockCommandClient := mocks.MockCommandClient{}
+	gitClient := git.GitServiceImpl{CommandClient: mockCommandClient}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.RunFakeCommand = tc.command
+			result, err := gitClient.GetCommits(context.Background(), "master")
+
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Wanted error to be %t, got '%v'", tc.expectedError, err)
+			}
+
+			if !cmp.Equal(result, tc.expectedCommits) {
+				t.Errorf("Wanted commits '%v'. Got commits '%v' instead", tc.expectedCommits, result)
+			}
+
+		})
+	}
+}
+
+func TestGetRemoteUrl(t *testing.T) {
+	tests := map[string]struct {
+		command     func(command string, args ...string) ([]byte, error)
+		expectedUrl string
+	}{
+		"Return remote url": {func(command string, args ...string) ([]byte, error) {
+			return []byte("git@gitlab.com:group/jitlab.git\n"), nil
+		}, "git@gitlab.com:group/jitlab.git"},
+		"Return error": {func(command string, args ...string) ([]byte, error) {
+			return []byte("error: No such remote 'origin'"), errors.New("exit status 2")
+		}, ""},
+	}
+	mockCommandClient := mocks.MockCommandClient{}
+	gitClient := git.GitServiceImpl{CommandClient: mockCommandClient}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.RunFakeCommand = tc.command
+			result, err := gitClient.GetRemoteUrl(context.Background(), "origin")
+
+			if tc.expectedUrl == "" && err == nil {
+				t.Errorf("Got no url nor error. Something unexpected happened!")
+			}
+
+			if result != tc.expectedUrl {
+				t.Errorf("Wanted url '%s'. Got url '%s' instead", tc.expectedUrl, result)
+			}
+
+		})
+	}
+}
+
+func TestGetGitDir(t *testing.T) {
+	workingDir, _ := os.Getwd()
+	tests := map[string]struct {
+		command     func(command string, args ...string) ([]byte, error)
+		expectedDir string
+	}{
+		"Return absolute dir": {func(command string, args ...string) ([]byte, error) {
+			return []byte("/home/user/jitlab/.git\n"), nil
+		}, "/home/user/jitlab/.git"},
+		"Return relative dir made absolute": {func(command string, args ...string) ([]byte, error) {
+			return []byte(".git\n"), nil
+		}, filepath.Join(workingDir, ".git")},
+		"Return error": {func(command string, args ...string) ([]byte, error) {
+			return []byte("fatal: not a git repository"), errors.New("exit status 128")
+		}, ""},
+	}
+	mockCommandClient := mocks.MockCommandClient{}
+	gitClient := git.GitServiceImpl{CommandClient: mockCommandClient}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.RunFakeCommand = tc.command
+			result, err := gitClient.GetGitDir(context.Background())
+
+			if tc.expectedDir == "" && err == nil {
+				t.Errorf("Got no dir nor error. Something unexpected happened!")
+			}
+
+			if result != tc.expectedDir {
+				t.Errorf("Wanted dir '%s'. Got dir '%s' instead", tc.expectedDir, result)
+			}
+
+		})
+	}
+}
+
 func initJiraIssue(key string, summary string) jira.Issue {
 	issue := jira.Issue{}
 	issue.ID = "id"
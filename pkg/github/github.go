@@ -0,0 +1,287 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+type GithubServiceImpl struct {
+	Client  rest.RestClient
+	BaseURL string
+	Token   string
+	Owner   string
+}
+
+type repository struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+type searchBase struct {
+	Items []repository `json:"items"`
+}
+
+type prRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head,omitempty"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+type issueRequest struct {
+	Labels    []string `json:"labels,omitempty"`
+	Milestone int      `json:"milestone,omitempty"`
+}
+
+type prResponse struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Url    string `json:"html_url"`
+	Base   struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (g GithubServiceImpl) CheckAuth(ctx context.Context) (string, error) {
+	url := g.BaseURL + "/user"
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, g.headers(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	currentUser := new(user)
+	err = g.Client.ProcessResponse(response, currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	return currentUser.Login, nil
+}
+
+func (g GithubServiceImpl) SearchProject(ctx context.Context, search string) ([]host.Repository, error) {
+	query := fmt.Sprintf("%s in:name", search)
+	if g.Owner != "" {
+		query = fmt.Sprintf("%s user:%s", query, g.Owner)
+	}
+	uri := fmt.Sprintf("/search/repositories?q=%s", url.QueryEscape(query))
+	url := g.BaseURL + uri
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, g.headers(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(searchBase)
+	err = g.Client.ProcessResponse(response, result)
+	if err != nil {
+		return nil, err
+	}
+
+	var repositories []host.Repository
+	for _, r := range result.Items {
+		repositories = append(repositories, r.toRepository())
+	}
+
+	return repositories, nil
+}
+
+func (g GithubServiceImpl) GetProject(ctx context.Context, fullPath string) (host.Repository, error) {
+	url := g.BaseURL + fmt.Sprintf("/repos/%s", fullPath)
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, g.headers(), nil)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	foundRepository := new(repository)
+	err = g.Client.ProcessResponse(response, foundRepository)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	return foundRepository.toRepository(), nil
+}
+
+// Labels and milestone are set on the underlying issue, with a second request.
+func (g GithubServiceImpl) CreateMergeRequest(ctx context.Context, repository host.Repository, options host.MergeRequestOptions) (host.MergeRequestResponse, error) {
+	if len(options.AssigneeIDs) > 0 || len(options.ReviewerIDs) > 0 {
+		return host.MergeRequestResponse{}, errors.New("assignee and reviewer IDs are not supported on GitHub")
+	}
+
+	uri := fmt.Sprintf("/repos/%s/pulls", repository.FullPath)
+	request := prRequest{
+		Title: options.Title,
+		Head:  options.SourceBranch,
+		Base:  options.TargetBranch,
+		Body:  options.Description,
+		Draft: options.Draft}
+
+	pullRequest, err := g.sendPullRequest(ctx, http.MethodPost, uri, request)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	if err := g.updateIssue(ctx, repository, pullRequest.Number, options); err != nil {
+		return pullRequest.toMergeRequestResponse(), err
+	}
+
+	return pullRequest.toMergeRequestResponse(), nil
+}
+
+func (g GithubServiceImpl) FindMergeRequest(ctx context.Context, repository host.Repository, sourceBranch string) (host.MergeRequestResponse, bool, error) {
+	owner := strings.Split(repository.FullPath, "/")[0]
+	uri := fmt.Sprintf("/repos/%s/pulls?state=open&head=%s", repository.FullPath, url.QueryEscape(owner+":"+sourceBranch))
+	url := g.BaseURL + uri
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, g.headers(), nil)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	pullRequests := new([]prResponse)
+	err = g.Client.ProcessResponse(response, pullRequests)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	if len(*pullRequests) == 0 {
+		return host.MergeRequestResponse{}, false, nil
+	}
+
+	return (*pullRequests)[0].toMergeRequestResponse(), true, nil
+}
+
+func (g GithubServiceImpl) UpdateMergeRequest(ctx context.Context, repository host.Repository, iid int, options host.MergeRequestOptions) (host.MergeRequestResponse, error) {
+	if len(options.AssigneeIDs) > 0 || len(options.ReviewerIDs) > 0 {
+		return host.MergeRequestResponse{}, errors.New("assignee and reviewer IDs are not supported on GitHub")
+	}
+
+	uri := fmt.Sprintf("/repos/%s/pulls/%d", repository.FullPath, iid)
+	request := prRequest{
+		Title: options.Title,
+		Base:  options.TargetBranch,
+		Body:  options.Description}
+
+	pullRequest, err := g.sendPullRequest(ctx, http.MethodPatch, uri, request)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	if err := g.updateIssue(ctx, repository, pullRequest.Number, options); err != nil {
+		return pullRequest.toMergeRequestResponse(), err
+	}
+
+	return pullRequest.toMergeRequestResponse(), nil
+}
+
+func (g GithubServiceImpl) sendPullRequest(ctx context.Context, method string, uri string, request prRequest) (prResponse, error) {
+	pullRequest := new(prResponse)
+	url := g.BaseURL + uri
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	req, err := g.Client.CreateRequest(ctx, method, url, g.headers(), bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	err = g.Client.ProcessResponse(response, pullRequest)
+	if err != nil {
+		return *pullRequest, err
+	}
+
+	return *pullRequest, nil
+}
+
+func (g GithubServiceImpl) updateIssue(ctx context.Context, repository host.Repository, number int, options host.MergeRequestOptions) error {
+	if len(options.Labels) == 0 && options.MilestoneID == 0 {
+		return nil
+	}
+
+	uri := fmt.Sprintf("/repos/%s/issues/%d", repository.FullPath, number)
+	url := g.BaseURL + uri
+	request := issueRequest{Labels: options.Labels, Milestone: options.MilestoneID}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodPatch, url, g.headers(), bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return g.Client.ProcessResponse(response, nil)
+}
+
+func (g GithubServiceImpl) headers() map[string]string {
+	return map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", g.Token),
+		"Accept":        "application/vnd.github+json",
+		"Content-Type":  "application/json"}
+}
+
+func (r repository) toRepository() host.Repository {
+	return host.Repository{
+		ID:          r.ID,
+		Description: r.Description,
+		Name:        r.Name,
+		Path:        r.Name,
+		FullPath:    r.FullName,
+		Host:        host.Github}
+}
+
+func (p prResponse) toMergeRequestResponse() host.MergeRequestResponse {
+	return host.MergeRequestResponse{IID: p.Number, Title: p.Title, TargetBranch: p.Base.Ref, Url: p.Url}
+}
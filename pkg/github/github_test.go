@@ -0,0 +1,88 @@
+package github_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/github"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/mocks"
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+var repository = host.Repository{ID: 1, Name: "jitlab", FullPath: "boh717/jitlab", Host: host.Github}
+
+func TestCreateMergeRequest(t *testing.T) {
+	tests := map[string]struct {
+		options          host.MergeRequestOptions
+		expectedRequests []string
+		expectedError    bool
+	}{
+		"Pull request only": {host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "main", Title: "Title"},
+			[]string{"POST /repos/boh717/jitlab/pulls"}, false},
+		"Pull request with labels": {host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "main", Title: "Title", Labels: []string{"bug"}},
+			[]string{"POST /repos/boh717/jitlab/pulls", "PATCH /repos/boh717/jitlab/issues/7"}, false},
+		"Pull request with reviewer IDs": {host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "main", Title: "Title", ReviewerIDs: []int{1}},
+			nil, true},
+	}
+	githubService := github.GithubServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req.Method+" "+req.URL.Path)
+				return mocks.JsonResponse(201, `{"number":7,"title":"Title","html_url":"https://github.com/boh717/jitlab/pull/7","base":{"ref":"main"}}`), nil
+			}
+
+			result, err := githubService.CreateMergeRequest(context.Background(), repository, tc.options)
+
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Got pull request '%+v', but wanted an error", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if result.IID != 7 || result.Url != "https://github.com/boh717/jitlab/pull/7" || result.TargetBranch != "main" {
+				t.Errorf("Got unexpected pull request '%+v'", result)
+			}
+
+			if len(requests) != len(tc.expectedRequests) {
+				t.Fatalf("Got requests %v, wanted %v", requests, tc.expectedRequests)
+			}
+			for i := range requests {
+				if requests[i] != tc.expectedRequests[i] {
+					t.Errorf("Got request '%s', wanted '%s'", requests[i], tc.expectedRequests[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindMergeRequest(t *testing.T) {
+	var gotHead string
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		gotHead = req.URL.Query().Get("head")
+		return mocks.JsonResponse(200, `[]`), nil
+	}
+	githubService := github.GithubServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	_, found, err := githubService.FindMergeRequest(context.Background(), repository, "feature")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if found {
+		t.Errorf("Found a pull request, but wanted none")
+	}
+
+	if gotHead != "boh717:feature" {
+		t.Errorf("Wanted head 'boh717:feature', got '%s'", gotHead)
+	}
+}
@@ -2,18 +2,17 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/boh717/jitlab/pkg/host"
 	"github.com/boh717/jitlab/pkg/rest"
 )
 
-type GitlabService interface {
-	SearchProject(search string) ([]Repository, error)
-	CreateMergeRequest(projectId string, sourceBranch string, targetBranch string, title string, removeSourceBranch bool, squash bool) (MergeRequestResponse, error)
-}
-
 type GitlabServiceImpl struct {
 	Client  rest.RestClient
 	BaseURL string
@@ -21,11 +20,12 @@ type GitlabServiceImpl struct {
 	Group   string
 }
 
-type Repository struct {
-	ID          int    `json:"id"`
-	Description string `json:"description"`
-	Name        string `json:"name"`
-	Path        string `json:"path"`
+type project struct {
+	ID                int    `json:"id"`
+	Description       string `json:"description"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
 }
 
 type mrRequest struct {
@@ -33,20 +33,96 @@ type mrRequest struct {
 	SourceBranch       string `json:"source_branch"`
 	TargetBranch       string `json:"target_branch"`
 	Title              string `json:"title"`
+	Description        string `json:"description,omitempty"`
+	Labels             string `json:"labels,omitempty"`
+	AssigneeIDs        []int  `json:"assignee_ids,omitempty"`
+	ReviewerIDs        []int  `json:"reviewer_ids,omitempty"`
+	MilestoneID        int    `json:"milestone_id,omitempty"`
 	RemoveSourceBranch bool   `json:"remove_source_branch"`
 	Squash             bool   `json:"squash"`
 }
 
-type MergeRequestResponse struct {
-	Url string `json:"web_url"`
+type group struct {
+	FullPath string `json:"full_path"`
+}
+
+type user struct {
+	Username string `json:"username"`
+}
+
+type mrResponse struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	TargetBranch string `json:"target_branch"`
+	Url          string `json:"web_url"`
+}
+
+func (p project) toRepository() host.Repository {
+	return host.Repository{
+		ID:          p.ID,
+		Description: p.Description,
+		Name:        p.Name,
+		Path:        p.Path,
+		FullPath:    p.PathWithNamespace,
+		Host:        host.Gitlab}
+}
+
+func (m mrResponse) toMergeRequestResponse() host.MergeRequestResponse {
+	return host.MergeRequestResponse{IID: m.IID, Title: m.Title, TargetBranch: m.TargetBranch, Url: m.Url}
+}
+
+func (g GitlabServiceImpl) CheckAuth(ctx context.Context) (string, error) {
+	url := g.BaseURL + "/user"
+	headers := map[string]string{"PRIVATE-TOKEN": g.Token}
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	currentUser := new(user)
+	err = g.Client.ProcessResponse(response, currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	return currentUser.Username, nil
+}
+
+func (g GitlabServiceImpl) GetGroup(ctx context.Context) (string, error) {
+	url := g.BaseURL + fmt.Sprintf("/groups/%s", g.Group)
+	headers := map[string]string{"PRIVATE-TOKEN": g.Token}
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	currentGroup := new(group)
+	err = g.Client.ProcessResponse(response, currentGroup)
+	if err != nil {
+		return "", err
+	}
+
+	return currentGroup.FullPath, nil
 }
 
-func (g GitlabServiceImpl) SearchProject(search string) ([]Repository, error) {
+func (g GitlabServiceImpl) SearchProject(ctx context.Context, search string) ([]host.Repository, error) {
 	uri := fmt.Sprintf("/groups/%s/search?scope=projects&search=%s", g.Group, search)
 	url := g.BaseURL + uri
 	headers := map[string]string{"PRIVATE-TOKEN": g.Token}
 
-	req, err := g.Client.CreateRequest(http.MethodGet, url, headers, nil)
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -56,47 +132,151 @@ func (g GitlabServiceImpl) SearchProject(search string) ([]Repository, error) {
 		return nil, err
 	}
 
-	repositories := new([]Repository)
-	err = g.Client.ProcessResponse(response, repositories)
+	projects := new([]project)
+	err = g.Client.ProcessResponse(response, projects)
 	if err != nil {
 		return nil, err
 	}
 
-	return *repositories, nil
+	var repositories []host.Repository
+	for _, p := range *projects {
+		repositories = append(repositories, p.toRepository())
+	}
+
+	return repositories, nil
+}
+
+func (g GitlabServiceImpl) GetProject(ctx context.Context, fullPath string) (host.Repository, error) {
+	uri := fmt.Sprintf("/projects/%s", url.PathEscape(fullPath))
+	url := g.BaseURL + uri
+	headers := map[string]string{"PRIVATE-TOKEN": g.Token}
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	foundProject := new(project)
+	err = g.Client.ProcessResponse(response, foundProject)
+	if err != nil {
+		return host.Repository{}, err
+	}
+
+	return foundProject.toRepository(), nil
 }
 
-func (g GitlabServiceImpl) CreateMergeRequest(projectId string, sourceBranch string, targetBranch string, title string, removeSourceBranch bool, squash bool) (MergeRequestResponse, error) {
-	mrResponse := new(MergeRequestResponse)
+func (g GitlabServiceImpl) CreateMergeRequest(ctx context.Context, repository host.Repository, options host.MergeRequestOptions) (host.MergeRequestResponse, error) {
+	projectId := fmt.Sprintf("%d", repository.ID)
+	mrResponse := new(mrResponse)
 	uri := fmt.Sprintf("/projects/%s/merge_requests", projectId)
 	url := g.BaseURL + uri
 	headers := map[string]string{"PRIVATE-TOKEN": g.Token, "Content-Type": "application/json"}
-	request := mrRequest{
-		ID:                 projectId,
-		SourceBranch:       sourceBranch,
-		TargetBranch:       targetBranch,
-		Title:              title,
-		RemoveSourceBranch: removeSourceBranch,
-		Squash:             squash}
+	request := buildMergeRequest(projectId, options)
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodPost, url, headers, bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	err = g.Client.ProcessResponse(response, mrResponse)
+	if err != nil {
+		return host.MergeRequestResponse{}, err
+	}
+
+	return mrResponse.toMergeRequestResponse(), nil
+}
+
+func (g GitlabServiceImpl) FindMergeRequest(ctx context.Context, repository host.Repository, sourceBranch string) (host.MergeRequestResponse, bool, error) {
+	projectId := fmt.Sprintf("%d", repository.ID)
+	uri := fmt.Sprintf("/projects/%s/merge_requests?state=opened&source_branch=%s", projectId, url.QueryEscape(sourceBranch))
+	url := g.BaseURL + uri
+	headers := map[string]string{"PRIVATE-TOKEN": g.Token}
+
+	req, err := g.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	response, err := g.Client.DoRequest(req)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	mergeRequests := new([]mrResponse)
+	err = g.Client.ProcessResponse(response, mergeRequests)
+	if err != nil {
+		return host.MergeRequestResponse{}, false, err
+	}
+
+	if len(*mergeRequests) == 0 {
+		return host.MergeRequestResponse{}, false, nil
+	}
+
+	return (*mergeRequests)[0].toMergeRequestResponse(), true, nil
+}
+
+func (g GitlabServiceImpl) UpdateMergeRequest(ctx context.Context, repository host.Repository, iid int, options host.MergeRequestOptions) (host.MergeRequestResponse, error) {
+	projectId := fmt.Sprintf("%d", repository.ID)
+	mrResponse := new(mrResponse)
+	uri := fmt.Sprintf("/projects/%s/merge_requests/%d", projectId, iid)
+	url := g.BaseURL + uri
+	headers := map[string]string{"PRIVATE-TOKEN": g.Token, "Content-Type": "application/json"}
+	request := buildMergeRequest(projectId, options)
 
 	jsonRequest, err := json.Marshal(request)
 	if err != nil {
-		return *mrResponse, err
+		return host.MergeRequestResponse{}, err
 	}
 
-	req, err := g.Client.CreateRequest(http.MethodPost, url, headers, bytes.NewBuffer(jsonRequest))
+	req, err := g.Client.CreateRequest(ctx, http.MethodPut, url, headers, bytes.NewBuffer(jsonRequest))
 	if err != nil {
-		return *mrResponse, err
+		return host.MergeRequestResponse{}, err
 	}
 
 	response, err := g.Client.DoRequest(req)
 	if err != nil {
-		return *mrResponse, err
+		return host.MergeRequestResponse{}, err
 	}
 
 	err = g.Client.ProcessResponse(response, mrResponse)
 	if err != nil {
-		return *mrResponse, err
+		return host.MergeRequestResponse{}, err
 	}
 
-	return *mrResponse, nil
+	return mrResponse.toMergeRequestResponse(), nil
+}
+
+func buildMergeRequest(projectId string, options host.MergeRequestOptions) mrRequest {
+	title := options.Title
+	if options.Draft && !strings.HasPrefix(strings.ToLower(title), "draft:") {
+		title = "Draft: " + title
+	}
+
+	return mrRequest{
+		ID:                 projectId,
+		SourceBranch:       options.SourceBranch,
+		TargetBranch:       options.TargetBranch,
+		Title:              title,
+		Description:        options.Description,
+		Labels:             strings.Join(options.Labels, ","),
+		AssigneeIDs:        options.AssigneeIDs,
+		ReviewerIDs:        options.ReviewerIDs,
+		MilestoneID:        options.MilestoneID,
+		RemoveSourceBranch: options.RemoveSourceBranch,
+		Squash:             options.Squash}
 }
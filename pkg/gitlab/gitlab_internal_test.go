@@ -0,0 +1,42 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildMergeRequest(t *testing.T) {
+	tests := map[string]struct {
+		options host.MergeRequestOptions
+		want    mrRequest
+	}{
+		"Only branches and title": {
+			host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "JT-01: complete this task", Squash: true},
+			mrRequest{ID: "42", SourceBranch: "feature", TargetBranch: "master", Title: "JT-01: complete this task", Squash: true},
+		},
+		"Labels, assignees and reviewers": {
+			host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "Title", Labels: []string{"backend", "bug"}, AssigneeIDs: []int{1}, ReviewerIDs: []int{2, 3}, MilestoneID: 7},
+			mrRequest{ID: "42", SourceBranch: "feature", TargetBranch: "master", Title: "Title", Labels: "backend,bug", AssigneeIDs: []int{1}, ReviewerIDs: []int{2, 3}, MilestoneID: 7},
+		},
+		"Draft": {
+			host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "Title", Draft: true},
+			mrRequest{ID: "42", SourceBranch: "feature", TargetBranch: "master", Title: "Draft: Title"},
+		},
+		"Draft already in title": {
+			host.MergeRequestOptions{SourceBranch: "feature", TargetBranch: "master", Title: "Draft: Title", Draft: true},
+			mrRequest{ID: "42", SourceBranch: "feature", TargetBranch: "master", Title: "Draft: Title"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := buildMergeRequest("42", tc.options)
+
+			if !cmp.Equal(result, tc.want) {
+				t.Errorf("Result request '%+v' is different from expected one '%+v'", result, tc.want)
+			}
+		})
+	}
+}
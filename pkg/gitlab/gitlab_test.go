@@ -0,0 +1,101 @@
+package gitlab_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/gitlab"
+	"github.com/boh717/jitlab/pkg/host"
+	"github.com/boh717/jitlab/pkg/mocks"
+	"github.com/boh717/jitlab/pkg/rest"
+)
+
+func TestFindMergeRequest(t *testing.T) {
+	tests := map[string]struct {
+		body          string
+		expectedFound bool
+		expectedUrl   string
+	}{
+		"Open merge request": {`[{"iid":3,"web_url":"https://gitlab.example.com/group/project/-/merge_requests/3"}]`, true, "https://gitlab.example.com/group/project/-/merge_requests/3"},
+		"No merge request":   {`[]`, false, ""},
+	}
+	gitlabService := gitlab.GitlabServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotQuery string
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				gotQuery = req.URL.Query().Get("source_branch")
+				return mocks.JsonResponse(200, tc.body), nil
+			}
+
+			result, found, err := gitlabService.FindMergeRequest(context.Background(), host.Repository{ID: 42}, "prefix/JT-01-complete-this-task")
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if gotQuery != "prefix/JT-01-complete-this-task" {
+				t.Errorf("Wanted source branch 'prefix/JT-01-complete-this-task', got '%s'", gotQuery)
+			}
+
+			if found != tc.expectedFound || result.Url != tc.expectedUrl {
+				t.Errorf("Got merge request '%+v' (found %t), wanted url '%s' (found %t)", result, found, tc.expectedUrl, tc.expectedFound)
+			}
+		})
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	var gotPath string
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.EscapedPath()
+		return mocks.JsonResponse(200, `{"id":42,"name":"Jitlab","path":"jitlab","path_with_namespace":"group/subgroup/jitlab"}`), nil
+	}
+	gitlabService := gitlab.GitlabServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	result, err := gitlabService.GetProject(context.Background(), "group/subgroup/jitlab")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if gotPath != "/projects/group%2Fsubgroup%2Fjitlab" {
+		t.Errorf("Wanted path '/projects/group%%2Fsubgroup%%2Fjitlab', got '%s'", gotPath)
+	}
+
+	if result.ID != 42 || result.FullPath != "group/subgroup/jitlab" || result.Host != host.Gitlab {
+		t.Errorf("Got unexpected repository '%+v'", result)
+	}
+}
+
+func TestCheckAuth(t *testing.T) {
+	tests := map[string]struct {
+		statusCode   int
+		body         string
+		expectedUser string
+	}{
+		"Valid token":   {200, `{"id":1,"username":"mario"}`, "mario"},
+		"Invalid token": {401, `{"message":"401 Unauthorized"}`, ""},
+	}
+	gitlabService := gitlab.GitlabServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				response := mocks.JsonResponse(200, tc.body)
+				response.StatusCode = tc.statusCode
+				return response, nil
+			}
+
+			result, err := gitlabService.CheckAuth(context.Background())
+
+			if tc.expectedUser == "" && err == nil {
+				t.Errorf("Got user '%s', but wanted an error", result)
+			}
+
+			if result != tc.expectedUser {
+				t.Errorf("Wanted user '%s', got '%s'", tc.expectedUser, result)
+			}
+		})
+	}
+}
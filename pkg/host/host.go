@@ -0,0 +1,75 @@
+package host
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	Gitlab    = "gitlab"
+	Github    = "github"
+	Bitbucket = "bitbucket"
+)
+
+// HostService is implemented by every code host jitlab can open merge (or
+// pull) requests on.
+type HostService interface {
+	// CheckAuth returns the user the token belongs to, empty if the host doesn't tell.
+	CheckAuth(ctx context.Context) (string, error)
+	SearchProject(ctx context.Context, search string) ([]Repository, error)
+	// GetProject looks a project up by its path, e.g. "group/project".
+	GetProject(ctx context.Context, fullPath string) (Repository, error)
+	CreateMergeRequest(ctx context.Context, repository Repository, options MergeRequestOptions) (MergeRequestResponse, error)
+	// FindMergeRequest returns the open merge request from sourceBranch, if any.
+	FindMergeRequest(ctx context.Context, repository Repository, sourceBranch string) (MergeRequestResponse, bool, error)
+	UpdateMergeRequest(ctx context.Context, repository Repository, iid int, options MergeRequestOptions) (MergeRequestResponse, error)
+}
+
+// Repository is what `jitlab init` stores. An empty Host means GitLab.
+type Repository struct {
+	ID          int                    `json:"id"`
+	Description string                 `json:"description"`
+	Name        string                 `json:"name"`
+	Path        string                 `json:"path"`
+	FullPath    string                 `json:"fullPath,omitempty"`
+	Host        string                 `json:"host,omitempty"`
+	Profile     string                 `json:"profile,omitempty"`
+	Config      map[string]interface{} `json:"config,omitempty"`
+}
+
+type MergeRequestOptions struct {
+	SourceBranch       string
+	TargetBranch       string
+	Title              string
+	Description        string
+	Labels             []string
+	AssigneeIDs        []int
+	ReviewerIDs        []int
+	MilestoneID        int
+	RemoveSourceBranch bool
+	Squash             bool
+	Draft              bool
+}
+
+type MergeRequestResponse struct {
+	IID          int
+	Title        string
+	TargetBranch string
+	Url          string
+}
+
+func (r Repository) HostName() string {
+	if r.Host == "" {
+		return Gitlab
+	}
+
+	return r.Host
+}
+
+func (r Repository) String() string {
+	if r.FullPath != "" {
+		return fmt.Sprintf("%s (%s)", r.FullPath, r.HostName())
+	}
+
+	return fmt.Sprintf("%s (%s)", r.Name, r.HostName())
+}
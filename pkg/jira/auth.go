@@ -0,0 +1,60 @@
+package jira
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	BasicAuthType  = "basic"
+	BearerAuthType = "bearer"
+)
+
+type Auth interface {
+	Header() string
+}
+
+// A Token already holding base64("username:token") is sent as it is.
+type BasicAuth struct {
+	Username string
+	Token    string
+}
+
+type BearerAuth struct {
+	Token string
+}
+
+func (a BasicAuth) Header() string {
+	if a.Username == "" || isEncodedCredential(a.Username, a.Token) {
+		return fmt.Sprintf("Basic %s", a.Token)
+	}
+
+	credential := base64.StdEncoding.EncodeToString([]byte(a.Username + ":" + a.Token))
+
+	return fmt.Sprintf("Basic %s", credential)
+}
+
+func (a BearerAuth) Header() string {
+	return fmt.Sprintf("Bearer %s", a.Token)
+}
+
+func NewAuth(authType string, username string, token string) (Auth, error) {
+	switch strings.ToLower(authType) {
+	case "", BasicAuthType:
+		return BasicAuth{Username: username, Token: token}, nil
+	case BearerAuthType:
+		return BearerAuth{Token: token}, nil
+	default:
+		return nil, fmt.Errorf("unknown Jira auth \"%s\", use \"%s\" or \"%s\"", authType, BasicAuthType, BearerAuthType)
+	}
+}
+
+func isEncodedCredential(username string, token string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(string(decoded), username+":")
+}
@@ -1,6 +1,9 @@
 package jira
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,16 +13,29 @@ import (
 )
 
 type JiraService interface {
-	GetBoards() ([]Board, error)
-	GetBoardColumns(board Board) ([]Column, error)
-	GetIssues(flowType string, projectKey string, columns []string, currentUser bool) ([]Issue, error)
+	GetBoards(ctx context.Context, name string) ([]Board, error)
+	GetBoardColumns(ctx context.Context, board Board) ([]Column, error)
+	GetStatuses(ctx context.Context) ([]Status, error)
+	GetIssues(ctx context.Context, flowType string, projectKey string, columns []string, currentUser bool, limit int) ([]Issue, int, error)
+	GetIssue(ctx context.Context, key string) (Issue, error)
+	GetIssueUrl(key string) string
+	GetTransitions(ctx context.Context, key string) ([]Transition, error)
+	DoTransition(ctx context.Context, key string, transition Transition) error
+	GetCurrentUser(ctx context.Context) (User, error)
+	AssignIssue(ctx context.Context, key string, user User) error
+	AddRemoteLink(ctx context.Context, key string, linkUrl string, title string) error
 }
 
+const issuesPageSize = 50
+
+const defaultApiVersion = "3"
+
+// Jira Server and Data Center use ApiVersion "2", usually with BearerAuth.
 type JiraServiceImpl struct {
-	Client   rest.RestClient
-	BaseURL  string
-	Token    string
-	Username string
+	Client     rest.RestClient
+	BaseURL    string
+	Auth       Auth
+	ApiVersion string
 }
 
 type Board struct {
@@ -44,7 +60,19 @@ type boardBase struct {
 	Values     []Board `json:"values"`
 }
 
+// Column is a board column. It groups one or more statuses, whose names may
+// differ from the column name (e.g. "Doing" holding "In Progress").
 type Column struct {
+	Name     string         `json:"name"`
+	Statuses []ColumnStatus `json:"statuses,omitempty"`
+}
+
+type ColumnStatus struct {
+	ID string `json:"id"`
+}
+
+type Status struct {
+	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
@@ -54,25 +82,145 @@ type boardConfig struct {
 	} `json:"columnConfig"`
 }
 
+// User is identified by AccountID on Jira Cloud and by Name on Jira Server.
+type User struct {
+	AccountID   string `json:"accountId"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
 type Issue struct {
 	ID     string `json:"id"`
 	Key    string `json:"key"`
 	Fields struct {
-		Summary string `json:"summary"`
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"`
+		Assignee    *User           `json:"assignee"`
 	} `json:"fields"`
 }
 
+type documentNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Content []documentNode `json:"content"`
+}
+
+// The description is plain text in API v2 and a document tree in v3.
+func (i Issue) DescriptionText() string {
+	if len(i.Fields.Description) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(i.Fields.Description, &text); err == nil {
+		return text
+	}
+
+	var document documentNode
+	if err := json.Unmarshal(i.Fields.Description, &document); err != nil {
+		return ""
+	}
+
+	var description strings.Builder
+	writeDocumentText(&description, document)
+
+	return strings.TrimSpace(description.String())
+}
+
+func writeDocumentText(description *strings.Builder, node documentNode) {
+	switch node.Type {
+	case "text":
+		description.WriteString(node.Text)
+	case "hardBreak":
+		description.WriteString("\n")
+	}
+
+	for _, child := range node.Content {
+		writeDocumentText(description, child)
+	}
+
+	switch node.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote", "rule", "listItem":
+		description.WriteString("\n")
+	}
+}
+
 type issueBase struct {
-	Total  int     `json:"total"`
-	Issues []Issue `json:"issues"`
+	StartAt int     `json:"startAt"`
+	Total   int     `json:"total"`
+	Issues  []Issue `json:"issues"`
+}
+
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		Name string `json:"name"`
+	} `json:"to"`
+}
+
+type transitionBase struct {
+	Transitions []Transition `json:"transitions"`
+}
+
+type assigneeRequest struct {
+	AccountID string `json:"accountId,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+type remoteLinkRequest struct {
+	Object struct {
+		Url   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"object"`
+}
+
+type transitionRequest struct {
+	Transition struct {
+		ID string `json:"id"`
+	} `json:"transition"`
+}
+
+func (j JiraServiceImpl) GetBoards(ctx context.Context, name string) ([]Board, error) {
+	var boards []Board
+	headers := j.headers()
+
+	for startAt := 0; ; {
+		url := j.BaseURL + buildBoardsUri(name, startAt)
+
+		req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := j.Client.DoRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		board := new(boardBase)
+		err = j.Client.ProcessResponse(response, board)
+		if err != nil {
+			return nil, err
+		}
+
+		boards = append(boards, board.Values...)
+		startAt = board.StartAt + len(board.Values)
+
+		if board.IsLast || len(board.Values) == 0 {
+			break
+		}
+	}
+
+	return boards, nil
 }
 
-func (j JiraServiceImpl) GetBoards() ([]Board, error) {
-	uri := "/rest/agile/1.0/board"
+func (j JiraServiceImpl) GetBoardColumns(ctx context.Context, board Board) ([]Column, error) {
+	uri := fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", board.ID)
 	url := j.BaseURL + uri
-	headers := map[string]string{"Authorization": fmt.Sprintf("Basic %s", j.Token)}
+	headers := j.headers()
 
-	req, err := j.Client.CreateRequest(http.MethodGet, url, headers, nil)
+	req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,21 +230,20 @@ func (j JiraServiceImpl) GetBoards() ([]Board, error) {
 		return nil, err
 	}
 
-	board := new(boardBase)
-	err = j.Client.ProcessResponse(response, board)
+	boardConfig := new(boardConfig)
+	err = j.Client.ProcessResponse(response, boardConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return board.Values, nil
+	return boardConfig.ColumnConfig.Columns, nil
 }
 
-func (j JiraServiceImpl) GetBoardColumns(board Board) ([]Column, error) {
-	uri := fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", board.ID)
-	url := j.BaseURL + uri
-	headers := map[string]string{"Authorization": fmt.Sprintf("Basic %s", j.Token)}
+func (j JiraServiceImpl) GetStatuses(ctx context.Context) ([]Status, error) {
+	url := j.BaseURL + j.apiUri("/status")
+	headers := j.headers()
 
-	req, err := j.Client.CreateRequest(http.MethodGet, url, headers, nil)
+	req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -106,22 +253,117 @@ func (j JiraServiceImpl) GetBoardColumns(board Board) ([]Column, error) {
 		return nil, err
 	}
 
-	boardConfig := new(boardConfig)
-	err = j.Client.ProcessResponse(response, boardConfig)
+	statuses := new([]Status)
+	err = j.Client.ProcessResponse(response, statuses)
 	if err != nil {
 		return nil, err
 	}
 
-	return boardConfig.ColumnConfig.Columns, nil
+	return *statuses, nil
+}
+
+// BoardStatuses returns the statuses of the board columns, without duplicates.
+func BoardStatuses(columns []Column, statuses []Status) []Status {
+	byID := map[string]Status{}
+	for _, s := range statuses {
+		byID[s.ID] = s
+	}
+
+	var boardStatuses []Status
+	seen := map[string]bool{}
+	for _, column := range columns {
+		for _, columnStatus := range column.Statuses {
+			status, found := byID[columnStatus.ID]
+			if !found || seen[status.ID] {
+				continue
+			}
+			seen[status.ID] = true
+			boardStatuses = append(boardStatuses, status)
+		}
+	}
+
+	return boardStatuses
 }
 
-func (j JiraServiceImpl) GetIssues(flowType string, projectKey string, columns []string, currentUser bool) ([]Issue, error) {
+// A limit lower than 1 means no limit.
+func (j JiraServiceImpl) GetIssues(ctx context.Context, flowType string, projectKey string, columns []string, currentUser bool, limit int) ([]Issue, int, error) {
+	var issues []Issue
 	searchString := buildSearchString(flowType, projectKey, columns, currentUser)
-	uri := fmt.Sprintf("/rest/api/3/search?jql=%s&fields=summary,assignee&maxResults=50", url.QueryEscape(searchString))
+	headers := j.headers()
+
+	for startAt := 0; ; {
+		pageSize := issuesPageSize
+		if limit > 0 && limit-len(issues) < pageSize {
+			pageSize = limit - len(issues)
+		}
+
+		uri := j.apiUri("/search?jql=%s&fields=summary,assignee&startAt=%d&maxResults=%d", url.QueryEscape(searchString), startAt, pageSize)
+		url := j.BaseURL + uri
+
+		req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		response, err := j.Client.DoRequest(req)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		issue := new(issueBase)
+		err = j.Client.ProcessResponse(response, issue)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		issues = append(issues, issue.Issues...)
+		startAt = issue.StartAt + len(issue.Issues)
+
+		if limit > 0 && len(issues) >= limit {
+			return issues[:limit], issue.Total, nil
+		}
+
+		if startAt >= issue.Total || len(issue.Issues) == 0 {
+			return issues, issue.Total, nil
+		}
+	}
+
+}
+
+func (j JiraServiceImpl) GetIssue(ctx context.Context, key string) (Issue, error) {
+	issue := new(Issue)
+	uri := j.apiUri("/issue/%s?fields=summary,assignee,description", url.PathEscape(key))
 	url := j.BaseURL + uri
-	headers := map[string]string{"Authorization": fmt.Sprintf("Basic %s", j.Token)}
+	headers := j.headers()
 
-	req, err := j.Client.CreateRequest(http.MethodGet, url, headers, nil)
+	req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+	if err != nil {
+		return *issue, err
+	}
+
+	response, err := j.Client.DoRequest(req)
+	if err != nil {
+		return *issue, err
+	}
+
+	err = j.Client.ProcessResponse(response, issue)
+	if err != nil {
+		return *issue, err
+	}
+
+	return *issue, nil
+}
+
+func (j JiraServiceImpl) GetIssueUrl(key string) string {
+	return fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(j.BaseURL, "/"), key)
+}
+
+func (j JiraServiceImpl) GetTransitions(ctx context.Context, key string) ([]Transition, error) {
+	uri := j.apiUri("/issue/%s/transitions", url.PathEscape(key))
+	url := j.BaseURL + uri
+	headers := j.headers()
+
+	req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -131,14 +373,164 @@ func (j JiraServiceImpl) GetIssues(flowType string, projectKey string, columns [
 		return nil, err
 	}
 
-	issue := new(issueBase)
-	err = j.Client.ProcessResponse(response, issue)
+	transitions := new(transitionBase)
+	err = j.Client.ProcessResponse(response, transitions)
 	if err != nil {
 		return nil, err
 	}
 
-	return issue.Issues, nil
+	return transitions.Transitions, nil
+}
+
+func (j JiraServiceImpl) DoTransition(ctx context.Context, key string, transition Transition) error {
+	uri := j.apiUri("/issue/%s/transitions", url.PathEscape(key))
+	url := j.BaseURL + uri
+	headers := j.headers()
+	headers["Content-Type"] = "application/json"
+	request := transitionRequest{}
+	request.Transition.ID = transition.ID
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := j.Client.CreateRequest(ctx, http.MethodPost, url, headers, bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return err
+	}
+
+	response, err := j.Client.DoRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return j.Client.ProcessResponse(response, nil)
+}
+
+func (j JiraServiceImpl) GetCurrentUser(ctx context.Context) (User, error) {
+	user := new(User)
+	uri := j.apiUri("/myself")
+	url := j.BaseURL + uri
+	headers := j.headers()
+
+	req, err := j.Client.CreateRequest(ctx, http.MethodGet, url, headers, nil)
+	if err != nil {
+		return *user, err
+	}
+
+	response, err := j.Client.DoRequest(req)
+	if err != nil {
+		return *user, err
+	}
+
+	err = j.Client.ProcessResponse(response, user)
+	if err != nil {
+		return *user, err
+	}
+
+	return *user, nil
+}
+
+func (j JiraServiceImpl) AssignIssue(ctx context.Context, key string, user User) error {
+	uri := j.apiUri("/issue/%s/assignee", url.PathEscape(key))
+	url := j.BaseURL + uri
+	headers := j.headers()
+	headers["Content-Type"] = "application/json"
+
+	request := assigneeRequest{AccountID: user.AccountID}
+	if user.AccountID == "" {
+		request.Name = user.Name
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := j.Client.CreateRequest(ctx, http.MethodPut, url, headers, bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return err
+	}
+
+	response, err := j.Client.DoRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return j.Client.ProcessResponse(response, nil)
+}
+
+func (j JiraServiceImpl) AddRemoteLink(ctx context.Context, key string, linkUrl string, title string) error {
+	uri := j.apiUri("/issue/%s/remotelink", url.PathEscape(key))
+	url := j.BaseURL + uri
+	headers := j.headers()
+	headers["Content-Type"] = "application/json"
+	request := remoteLinkRequest{}
+	request.Object.Url = linkUrl
+	request.Object.Title = title
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := j.Client.CreateRequest(ctx, http.MethodPost, url, headers, bytes.NewBuffer(jsonRequest))
+	if err != nil {
+		return err
+	}
+
+	response, err := j.Client.DoRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return j.Client.ProcessResponse(response, nil)
+}
+
+func (j JiraServiceImpl) headers() map[string]string {
+	headers := map[string]string{}
+	if j.Auth != nil {
+		headers["Authorization"] = j.Auth.Header()
+	}
+
+	return headers
+}
+
+func (j JiraServiceImpl) apiUri(format string, a ...interface{}) string {
+	version := j.ApiVersion
+	if version == "" {
+		version = defaultApiVersion
+	}
+
+	return fmt.Sprintf("/rest/api/%s", version) + fmt.Sprintf(format, a...)
+}
+
+// FindTransition matches both the target status and the transition name.
+func FindTransition(transitions []Transition, status string) (Transition, bool) {
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, status) {
+			return t, true
+		}
+	}
+
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, status) {
+			return t, true
+		}
+	}
+
+	return Transition{}, false
+}
+
+func buildBoardsUri(name string, startAt int) string {
+	params := url.Values{}
+	params.Set("startAt", fmt.Sprintf("%d", startAt))
+	if name != "" {
+		params.Set("name", name)
+	}
 
+	return "/rest/agile/1.0/board?" + params.Encode()
 }
 
 func buildSearchString(flowType string, projectKey string, columns []string, currentUser bool) string {
@@ -0,0 +1,316 @@
+package jira_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/boh717/jitlab/pkg/jira"
+	"github.com/boh717/jitlab/pkg/mocks"
+	"github.com/boh717/jitlab/pkg/rest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetBoards(t *testing.T) {
+	pages := map[string]string{
+		"0": `{"startAt":0,"isLast":false,"values":[{"id":1,"name":"First"},{"id":2,"name":"Second"}]}`,
+		"2": `{"startAt":2,"isLast":true,"values":[{"id":3,"name":"Third"}]}`,
+	}
+	var requestedNames []string
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		requestedNames = append(requestedNames, req.URL.Query().Get("name"))
+		return mocks.JsonResponse(200, pages[req.URL.Query().Get("startAt")]), nil
+	}
+	jiraService := jira.JiraServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	boards, err := jiraService.GetBoards(context.Background(), "team")
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if len(boards) != 3 {
+		t.Errorf("Wanted 3 boards, got %d: %+v", len(boards), boards)
+	}
+
+	for _, name := range requestedNames {
+		if name != "team" {
+			t.Errorf("Wanted name filter 'team', got '%s'", name)
+		}
+	}
+}
+
+func TestGetIssues(t *testing.T) {
+	pages := map[string]string{
+		"0": `{"startAt":0,"total":5,"issues":[{"key":"JT-1"},{"key":"JT-2"}]}`,
+		"2": `{"startAt":2,"total":5,"issues":[{"key":"JT-3"},{"key":"JT-4"}]}`,
+		"4": `{"startAt":4,"total":5,"issues":[{"key":"JT-5"}]}`,
+	}
+	tests := map[string]struct {
+		limit             int
+		expectedIssues    int
+		expectedPageSizes []string
+	}{
+		"Read all pages":        {limit: 0, expectedIssues: 5, expectedPageSizes: []string{"50", "50", "50"}},
+		"Stop at limit":         {limit: 3, expectedIssues: 3, expectedPageSizes: []string{"3", "1"}},
+		"Limit above the total": {limit: 10, expectedIssues: 5, expectedPageSizes: []string{"10", "8", "6"}},
+	}
+	jiraService := jira.JiraServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var pageSizes []string
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				pageSizes = append(pageSizes, req.URL.Query().Get("maxResults"))
+				return mocks.JsonResponse(200, pages[req.URL.Query().Get("startAt")]), nil
+			}
+
+			issues, total, err := jiraService.GetIssues(context.Background(), "kanban", "JT", []string{"ToDo"}, false, tc.limit)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if len(issues) != tc.expectedIssues {
+				t.Errorf("Wanted %d issues, got %d", tc.expectedIssues, len(issues))
+			}
+
+			if total != 5 {
+				t.Errorf("Wanted total 5, got %d", total)
+			}
+
+			if !cmp.Equal(pageSizes, tc.expectedPageSizes) {
+				t.Errorf("Wanted page sizes %v, got %v", tc.expectedPageSizes, pageSizes)
+			}
+		})
+	}
+}
+
+func TestGetIssue(t *testing.T) {
+	tests := map[string]struct {
+		statusCode      int
+		body            string
+		expectedSummary string
+	}{
+		"Existing issue": {200, `{"id":"10001","key":"JT-1","fields":{"summary":"Complete this task"}}`, "Complete this task"},
+		"Missing issue":  {404, `{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`, ""},
+	}
+	jiraService := jira.JiraServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				response := mocks.JsonResponse(200, tc.body)
+				response.StatusCode = tc.statusCode
+				return response, nil
+			}
+
+			issue, err := jiraService.GetIssue(context.Background(), "JT-1")
+
+			if tc.expectedSummary == "" && err == nil {
+				t.Errorf("Got issue '%+v', but wanted an error", issue)
+			}
+
+			if issue.Fields.Summary != tc.expectedSummary {
+				t.Errorf("Wanted summary '%s', got '%s'", tc.expectedSummary, issue.Fields.Summary)
+			}
+		})
+	}
+}
+
+func TestDescriptionText(t *testing.T) {
+	tests := map[string]struct {
+		description string
+		expected    string
+	}{
+		"No description":    {``, ""},
+		"Null description":  {`null`, ""},
+		"Plain description": {`"Some details"`, "Some details"},
+		"Document description": {`{"type":"doc","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"First "},{"type":"text","text":"line"}]},
+			{"type":"paragraph","content":[{"type":"text","text":"Second"},{"type":"hardBreak"},{"type":"text","text":"line"}]}]}`, "First line\nSecond\nline"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			issue := jira.Issue{}
+			issue.Fields.Description = []byte(tc.description)
+
+			result := issue.DescriptionText()
+
+			if result != tc.expected {
+				t.Errorf("Got description '%s', but wanted '%s'", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestAssignIssue(t *testing.T) {
+	var gotRequest *http.Request
+	var gotBody []byte
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		gotRequest = req
+		gotBody, _ = ioutil.ReadAll(req.Body)
+		response := mocks.JsonResponse(200, "")
+		response.StatusCode = 204
+		return response, nil
+	}
+	jiraService := jira.JiraServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com"}
+
+	err := jiraService.AssignIssue(context.Background(), "JT-1", jira.User{AccountID: "abc123"})
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if gotRequest.Method != http.MethodPut || gotRequest.URL.Path != "/rest/api/3/issue/JT-1/assignee" {
+		t.Errorf("Got unexpected request '%s %s'", gotRequest.Method, gotRequest.URL.Path)
+	}
+
+	if string(gotBody) != `{"accountId":"abc123"}` {
+		t.Errorf("Got unexpected body '%s'", gotBody)
+	}
+}
+
+func TestFindTransition(t *testing.T) {
+	transitions := []jira.Transition{
+		initTransition("11", "Start progress", "In Progress"),
+		initTransition("21", "Review", "Code Review"),
+	}
+	tests := map[string]struct {
+		status     string
+		expectedID string
+	}{
+		"Match target status":    {"in progress", "11"},
+		"Match transition name":  {"Review", "21"},
+		"No matching transition": {"Done", ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			transition, found := jira.FindTransition(transitions, tc.status)
+
+			if found != (tc.expectedID != "") {
+				t.Errorf("Wanted found to be %t, got %t", tc.expectedID != "", found)
+			}
+
+			if transition.ID != tc.expectedID {
+				t.Errorf("Wanted transition '%s', got '%s'", tc.expectedID, transition.ID)
+			}
+		})
+	}
+}
+
+func TestBoardStatuses(t *testing.T) {
+	columns := []jira.Column{
+		initColumn("To Do", "1"),
+		initColumn("Doing", "3", "10"),
+		initColumn("Done", "6", "99"),
+	}
+	statuses := []jira.Status{{ID: "1", Name: "Open"}, {ID: "3", Name: "In Progress"}, {ID: "6", Name: "Closed"}, {ID: "10", Name: "Code Review"}}
+
+	result := jira.BoardStatuses(columns, statuses)
+
+	expected := []jira.Status{{ID: "1", Name: "Open"}, {ID: "3", Name: "In Progress"}, {ID: "10", Name: "Code Review"}, {ID: "6", Name: "Closed"}}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("BoardStatuses() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func initColumn(name string, statusIDs ...string) jira.Column {
+	column := jira.Column{Name: name}
+	for _, id := range statusIDs {
+		column.Statuses = append(column.Statuses, jira.ColumnStatus{ID: id})
+	}
+
+	return column
+}
+
+func initTransition(id string, name string, to string) jira.Transition {
+	transition := jira.Transition{ID: id, Name: name}
+	transition.To.Name = to
+
+	return transition
+}
+
+func TestServerApi(t *testing.T) {
+	tests := map[string]struct {
+		auth           jira.Auth
+		apiVersion     string
+		expectedPath   string
+		expectedHeader string
+	}{
+		"Jira Cloud":  {jira.BasicAuth{Token: "dXNlcjp0b2tlbg=="}, "", "/rest/api/3/myself", "Basic dXNlcjp0b2tlbg=="},
+		"Jira Server": {jira.BearerAuth{Token: "personal-token"}, "2", "/rest/api/2/myself", "Bearer personal-token"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotRequest *http.Request
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				gotRequest = req
+				return mocks.JsonResponse(200, `{"name":"mario","displayName":"Mario Rossi"}`), nil
+			}
+			jiraService := jira.JiraServiceImpl{Client: rest.RestClientImpl{Client: mocks.MockRestClient{}}, BaseURL: "http://www.example.com", Auth: tc.auth, ApiVersion: tc.apiVersion}
+
+			_, err := jiraService.GetCurrentUser(context.Background())
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if gotRequest.URL.Path != tc.expectedPath {
+				t.Errorf("Wanted path '%s', got '%s'", tc.expectedPath, gotRequest.URL.Path)
+			}
+
+			if gotRequest.Header.Get("Authorization") != tc.expectedHeader {
+				t.Errorf("Wanted header '%s', got '%s'", tc.expectedHeader, gotRequest.Header.Get("Authorization"))
+			}
+		})
+	}
+}
+
+func TestNewAuth(t *testing.T) {
+	tests := map[string]struct {
+		authType      string
+		expected      jira.Auth
+		expectedError bool
+	}{
+		"Default":      {"", jira.BasicAuth{Username: "mario@example.com", Token: "token"}, false},
+		"Basic":        {"basic", jira.BasicAuth{Username: "mario@example.com", Token: "token"}, false},
+		"Bearer":       {"Bearer", jira.BearerAuth{Token: "token"}, false},
+		"Unknown auth": {"oauth", nil, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := jira.NewAuth(tc.authType, "mario@example.com", "token")
+
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Wanted error to be %t, got '%v'", tc.expectedError, err)
+			}
+
+			if result != tc.expected {
+				t.Errorf("Wanted auth '%+v', got '%+v'", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBasicAuthHeader(t *testing.T) {
+	tests := map[string]struct {
+		auth     jira.BasicAuth
+		expected string
+	}{
+		"Username and token":     {jira.BasicAuth{Username: "mario@example.com", Token: "api-token"}, "Basic bWFyaW9AZXhhbXBsZS5jb206YXBpLXRva2Vu"},
+		"Pre-encoded token":      {jira.BasicAuth{Username: "mario@example.com", Token: "bWFyaW9AZXhhbXBsZS5jb206YXBpLXRva2Vu"}, "Basic bWFyaW9AZXhhbXBsZS5jb206YXBpLXRva2Vu"},
+		"Token without username": {jira.BasicAuth{Token: "bWFyaW9AZXhhbXBsZS5jb206YXBpLXRva2Vu"}, "Basic bWFyaW9AZXhhbXBsZS5jb206YXBpLXRva2Vu"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tc.auth.Header()
+
+			if result != tc.expected {
+				t.Errorf("Wanted header '%s', got '%s'", tc.expected, result)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
 	"net/http"
 )
 
@@ -10,14 +13,26 @@ type MockRestClient struct{}
 type MockCommandClient struct{}
 
 var (
-	DoFakeRequest  func(req *http.Request) (*http.Response, error)
-	RunFakeCommand func(command string, args ...string) ([]byte, error)
+	DoFakeRequest           func(req *http.Request) (*http.Response, error)
+	RunFakeCommand          func(command string, args ...string) ([]byte, error)
+	RunFakeCommandWithInput func(input string, command string, args ...string) ([]byte, error)
 )
 
 func (m MockRestClient) Do(req *http.Request) (*http.Response, error) {
 	return DoFakeRequest(req)
 }
 
-func (c MockCommandClient) Run(command string, args ...string) ([]byte, error) {
+func (c MockCommandClient) Run(ctx context.Context, command string, args ...string) ([]byte, error) {
 	return RunFakeCommand(command, args...)
 }
+
+func (c MockCommandClient) RunWithInput(ctx context.Context, input string, command string, args ...string) ([]byte, error) {
+	return RunFakeCommandWithInput(input, command, args...)
+}
+
+func JsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
@@ -2,17 +2,26 @@ package question
 
 import (
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/boh717/jitlab/pkg/gitlab"
+	"github.com/boh717/jitlab/pkg/host"
 	"github.com/boh717/jitlab/pkg/jira"
 )
 
 type QuestionService interface {
 	AskForBoard(boards []jira.Board) (jira.Board, error)
 	AskForColumns(columns []jira.Column) ([]string, error)
-	AskForRepository(repositories []gitlab.Repository) (gitlab.Repository, error)
+	AskForRepository(repositories []host.Repository) (host.Repository, error)
 	AskForIssue(issues []jira.Issue) (jira.Issue, error)
+	AskForStatus(message string, statuses []jira.Status) (string, error)
+	AskForSecret(message string) (string, error)
+	AskForText(message string, defaultValue string, validator func(string) error) (string, error)
+	AskForConfirmation(message string, defaultValue bool) (bool, error)
 }
 
+const (
+	noStatus    = "Leave it as it is"
+	otherStatus = "Another status (type it)"
+)
+
 type QuestionServiceImpl struct{}
 
 func (q QuestionServiceImpl) AskForBoard(boards []jira.Board) (jira.Board, error) {
@@ -68,13 +77,17 @@ func (q QuestionServiceImpl) AskForColumns(columns []jira.Column) ([]string, err
 
 }
 
-func (q QuestionServiceImpl) AskForRepository(repositories []gitlab.Repository) (gitlab.Repository, error) {
+func (q QuestionServiceImpl) AskForRepository(repositories []host.Repository) (host.Repository, error) {
 
-	var chosenRepo gitlab.Repository
 	var repoNames []string
 
+	// Different owners often have repositories with the same name.
 	for _, value := range repositories {
-		repoNames = append(repoNames, value.Name)
+		if value.FullPath != "" {
+			repoNames = append(repoNames, value.FullPath)
+		} else {
+			repoNames = append(repoNames, value.Name)
+		}
 	}
 
 	question := &survey.Select{
@@ -82,16 +95,14 @@ func (q QuestionServiceImpl) AskForRepository(repositories []gitlab.Repository)
 		Options: repoNames,
 	}
 
-	answer := ""
+	answer := 0
 
 	err := survey.AskOne(question, &answer)
 	if err != nil {
-		return chosenRepo, err
+		return host.Repository{}, err
 	}
 
-	getChosenRepo(repositories, answer, &chosenRepo)
-
-	return chosenRepo, nil
+	return repositories[answer], nil
 
 }
 
@@ -122,13 +133,102 @@ func (q QuestionServiceImpl) AskForIssue(issues []jira.Issue) (jira.Issue, error
 
 }
 
-func getChosenRepo(repos []gitlab.Repository, repoName string, chosenRepo *gitlab.Repository) {
+// An empty answer means the issue shouldn't be moved.
+func (q QuestionServiceImpl) AskForStatus(message string, statuses []jira.Status) (string, error) {
 
-	for _, v := range repos {
-		if v.Name == repoName {
-			*chosenRepo = v
-		}
+	if len(statuses) == 0 {
+		return q.AskForText(message+" (leave empty to keep it as it is)", "", nil)
+	}
+
+	statusNames := []string{noStatus}
+
+	for _, value := range statuses {
+		statusNames = append(statusNames, value.Name)
+	}
+	statusNames = append(statusNames, otherStatus)
+
+	question := &survey.Select{
+		Message: message,
+		Options: statusNames,
+	}
+
+	answer := ""
+
+	err := survey.AskOne(question, &answer)
+	if err != nil {
+		return "", err
+	}
+
+	if answer == noStatus {
+		return "", nil
+	}
+
+	if answer == otherStatus {
+		return q.AskForText(message, "", nil)
+	}
+
+	return answer, nil
+
+}
+
+func (q QuestionServiceImpl) AskForSecret(message string) (string, error) {
+
+	question := &survey.Password{
+		Message: message,
+	}
+
+	answer := ""
+
+	err := survey.AskOne(question, &answer, survey.WithValidator(survey.Required))
+	if err != nil {
+		return "", err
+	}
+
+	return answer, nil
+
+}
+
+func (q QuestionServiceImpl) AskForText(message string, defaultValue string, validator func(string) error) (string, error) {
+
+	question := &survey.Input{
+		Message: message,
+		Default: defaultValue,
+	}
+
+	answer := ""
+
+	var opts []survey.AskOpt
+	if validator != nil {
+		opts = append(opts, survey.WithValidator(func(ans interface{}) error {
+			return validator(ans.(string))
+		}))
+	}
+
+	err := survey.AskOne(question, &answer, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return answer, nil
+
+}
+
+func (q QuestionServiceImpl) AskForConfirmation(message string, defaultValue bool) (bool, error) {
+
+	question := &survey.Confirm{
+		Message: message,
+		Default: defaultValue,
 	}
+
+	answer := false
+
+	err := survey.AskOne(question, &answer)
+	if err != nil {
+		return false, err
+	}
+
+	return answer, nil
+
 }
 
 func getChosenBoard(boards []jira.Board, boardName string, chosenBoard *jira.Board) {
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ApiError is returned for any non-2xx response. Use errors.As to inspect it.
+type ApiError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Messages   []string
+	Body       string
+}
+
+func (e *ApiError) Error() string {
+	message := e.Body
+	if len(e.Messages) > 0 {
+		message = strings.Join(e.Messages, "; ")
+	}
+
+	if e.Method == "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), message)
+	}
+
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode), message)
+}
+
+func (e *ApiError) IsUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+func (e *ApiError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+func (e *ApiError) IsConflict() bool {
+	return e.StatusCode == http.StatusConflict
+}
+
+func (e *ApiError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+func newApiError(resp *http.Response, body []byte) *ApiError {
+	apiError := &ApiError{
+		StatusCode: resp.StatusCode,
+		Messages:   parseErrorMessages(body),
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	if resp.Request != nil {
+		apiError.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			apiError.URL = resp.Request.URL.Redacted()
+		}
+	}
+
+	return apiError
+}
+
+// parseErrorMessages understands both Jira ({"errorMessages": [...], "errors": {...}})
+// and GitLab ({"message": ...} or {"error": ..., "error_description": ...}) bodies.
+func parseErrorMessages(body []byte) []string {
+	var payload struct {
+		ErrorMessages    []string          `json:"errorMessages"`
+		Errors           map[string]string `json:"errors"`
+		Message          interface{}       `json:"message"`
+		Error            string            `json:"error"`
+		ErrorDescription string            `json:"error_description"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+
+	messages := append([]string{}, payload.ErrorMessages...)
+	messages = append(messages, sortedFieldMessages(payload.Errors)...)
+	messages = append(messages, flattenMessage("", payload.Message)...)
+
+	if payload.Error != "" {
+		if payload.ErrorDescription != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", payload.Error, payload.ErrorDescription))
+		} else {
+			messages = append(messages, payload.Error)
+		}
+	}
+
+	return messages
+}
+
+func sortedFieldMessages(fields map[string]string) []string {
+	var messages []string
+	for field, message := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, message))
+	}
+	sort.Strings(messages)
+
+	return messages
+}
+
+func flattenMessage(prefix string, message interface{}) []string {
+	var messages []string
+
+	switch value := message.(type) {
+	case string:
+		if prefix != "" {
+			value = fmt.Sprintf("%s: %s", prefix, value)
+		}
+		messages = append(messages, value)
+	case []interface{}:
+		for _, item := range value {
+			messages = append(messages, flattenMessage(prefix, item)...)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			messages = append(messages, flattenMessage(key, value[key])...)
+		}
+	}
+
+	return messages
+}
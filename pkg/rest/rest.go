@@ -1,15 +1,15 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 )
 
 type RestClient interface {
-	CreateRequest(method string, url string, headers map[string]string, payload io.Reader) (*http.Request, error)
+	CreateRequest(ctx context.Context, method string, url string, headers map[string]string, payload io.Reader) (*http.Request, error)
 	DoRequest(req *http.Request) (*http.Response, error)
 	ProcessResponse(resp *http.Response, data interface{}) error
 }
@@ -22,9 +22,9 @@ type RestClientImpl struct {
 	Client httpClient
 }
 
-func (r RestClientImpl) CreateRequest(method string, url string, headers map[string]string, payload io.Reader) (*http.Request, error) {
+func (r RestClientImpl) CreateRequest(ctx context.Context, method string, url string, headers map[string]string, payload io.Reader) (*http.Request, error) {
 
-	req, err := http.NewRequest(method, url, payload)
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -51,11 +51,15 @@ func (r RestClientImpl) ProcessResponse(resp *http.Response, data interface{}) e
 
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode <= http.StatusNoContent {
 
+		if data == nil || len(responseBody) == 0 {
+			return nil
+		}
+
 		if err := json.Unmarshal(responseBody, data); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	return errors.New(string(responseBody))
+	return newApiError(resp, responseBody)
 }
@@ -2,12 +2,14 @@ package rest_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/boh717/jitlab/pkg/mocks"
 	"github.com/boh717/jitlab/pkg/rest"
@@ -50,7 +52,7 @@ func TestCreateRequest(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := restClient.CreateRequest(tc.method, "www.example.com", tc.headers, tc.payload)
+			got, err := restClient.CreateRequest(context.Background(), tc.method, "www.example.com", tc.headers, tc.payload)
 			if err != nil {
 				t.Fatalf("Got unexpected error: %v", err)
 			}
@@ -98,7 +100,7 @@ func TestWrongCreateRequest(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := restClient.CreateRequest(tc.method, tc.url, nil, nil)
+			got, err := restClient.CreateRequest(context.Background(), tc.method, tc.url, nil, nil)
 			if err == nil {
 				t.Errorf("Got unexpected response: %+v", got)
 			}
@@ -150,6 +152,7 @@ func TestProcessResponse(t *testing.T) {
 	}{
 		"Successful path":    {200, `{"firstName":"Mario","lastName":"Rossi","age":25}`, &person{FirstName: "Mario", LastName: "Rossi", Age: 25}, true},
 		"Malformed json":     {200, `{"firstName""Mario","lastName":"Rossi","age":25}`, nil, false},
+		"No content":         {204, "", &person{}, true},
 		"Resource not found": {404, "Resource not found", nil, false},
 	}
 	mockHttpClient := mocks.MockRestClient{}
@@ -178,3 +181,112 @@ func TestProcessResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessResponseApiError(t *testing.T) {
+	tests := map[string]struct {
+		statusCode       int
+		body             string
+		expectedMessages []string
+	}{
+		"Jira error":               {404, `{"errorMessages":["Issue does not exist"],"errors":{}}`, []string{"Issue does not exist"}},
+		"Jira field errors":        {400, `{"errorMessages":[],"errors":{"summary":"Field is required","assignee":"User not found"}}`, []string{"assignee: User not found", "summary: Field is required"}},
+		"GitLab message":           {401, `{"message":"401 Unauthorized"}`, []string{"401 Unauthorized"}},
+		"GitLab message list":      {409, `{"message":["Another open merge request already exists for this source branch: !3"]}`, []string{"Another open merge request already exists for this source branch: !3"}},
+		"GitLab validation errors": {400, `{"message":{"title":["can't be blank"]}}`, []string{"title: can't be blank"}},
+		"GitLab OAuth error":       {401, `{"error":"invalid_token","error_description":"Token was revoked"}`, []string{"invalid_token: Token was revoked"}},
+		"Plain text body":          {502, "Bad Gateway", nil},
+	}
+	restClient := rest.RestClientImpl{Client: mocks.MockRestClient{}}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			request, _ := http.NewRequest("GET", "http://www.example.com/api", nil)
+			response := &http.Response{StatusCode: tc.statusCode, Body: ioutil.NopCloser(bytes.NewReader([]byte(tc.body))), Request: request}
+
+			err := restClient.ProcessResponse(response, new(person))
+
+			var apiError *rest.ApiError
+			if !errors.As(err, &apiError) {
+				t.Fatalf("Got error '%v', wanted an ApiError", err)
+			}
+
+			if apiError.StatusCode != tc.statusCode || apiError.Method != "GET" || apiError.URL != "http://www.example.com/api" {
+				t.Errorf("Got unexpected error details '%+v'", apiError)
+			}
+
+			if !cmp.Equal(apiError.Messages, tc.expectedMessages) {
+				t.Errorf("Got messages '%v', wanted '%v'", apiError.Messages, tc.expectedMessages)
+			}
+		})
+	}
+}
+
+func TestRetryClient(t *testing.T) {
+	tests := map[string]struct {
+		method           string
+		responses        []int
+		retryAfter       string
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		"No retry on success":              {"GET", []int{200}, "", 200, 1},
+		"Retry GET on server error":        {"GET", []int{503, 502, 200}, "", 200, 3},
+		"Give up after max retries":        {"GET", []int{503, 503, 503, 503, 503}, "", 503, 3},
+		"No retry of POST on server error": {"POST", []int{503, 200}, "", 503, 1},
+		"Retry POST when rate limited":     {"POST", []int{429, 201}, "0", 201, 2},
+		"Give up when asked to wait long":  {"GET", []int{429, 200}, "3600", 429, 1},
+		"No retry on client error":         {"GET", []int{404, 200}, "", 404, 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+				status := tc.responses[attempts]
+				attempts++
+				response := &http.Response{StatusCode: status, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+				if tc.retryAfter != "" {
+					response.Header.Set("Retry-After", tc.retryAfter)
+				}
+				return response, nil
+			}
+			retryClient := rest.RetryClient{Client: mocks.MockRestClient{}, MaxRetries: 2, Backoff: time.Millisecond, MaxWait: time.Second}
+			request, _ := http.NewRequest(tc.method, "http://www.example.com", strings.NewReader(payload))
+
+			resp, err := retryClient.Do(request)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Got status %d, wanted %d", resp.StatusCode, tc.expectedStatus)
+			}
+
+			if attempts != tc.expectedAttempts {
+				t.Errorf("Got %d attempts, wanted %d", attempts, tc.expectedAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryClientNetworkError(t *testing.T) {
+	attempts := 0
+	mocks.DoFakeRequest = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return successfulResponse(req)
+	}
+	retryClient := rest.RetryClient{Client: mocks.MockRestClient{}, MaxRetries: 2, Backoff: time.Millisecond}
+	request, _ := http.NewRequest("GET", "http://www.example.com", nil)
+
+	resp, err := retryClient.Do(request)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != 200 || attempts != 2 {
+		t.Errorf("Got status %d after %d attempts, wanted 200 after 2", resp.StatusCode, attempts)
+	}
+}
@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryClient retries throttled requests and, for idempotent methods, network
+// and server errors, with exponential backoff.
+type RetryClient struct {
+	Client     httpClient
+	MaxRetries int
+	Backoff    time.Duration
+	MaxWait    time.Duration
+}
+
+func (r RetryClient) Do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := r.Client.Do(req)
+		if attempt >= r.MaxRetries || !r.canRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait, ok := r.waitFor(resp, attempt)
+		if !ok {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (r RetryClient) canRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !isIdempotent(req.Method) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// waitFor is false when the server asks to wait longer than MaxWait.
+func (r RetryClient) waitFor(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait, r.MaxWait <= 0 || wait <= r.MaxWait
+		}
+	}
+
+	wait := r.Backoff << attempt
+	if r.MaxWait > 0 && wait > r.MaxWait {
+		wait = r.MaxWait
+	}
+
+	return wait, true
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}